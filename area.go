@@ -0,0 +1,116 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// AreaKey is the data key used to tag an assertion with the area of the
+// code it guards. Areas are dot separated and hierarchical, so rules set on
+// "storage" also apply to "storage.compaction".
+//
+//	assert.Assert(ok, "level sizes out of order", assert.AreaKey, "storage.compaction")
+//
+// It is namespaced so that an assertion's own "area" data is reported rather
+// than taken as its area.
+const AreaKey = "assert.area"
+
+// defaultArea is reported for assertions that do not carry an area.
+const defaultArea = "Assert"
+
+var areaMu sync.Mutex
+var areaRules atomic.Pointer[map[string]bool]
+
+// EnableArea enables assertions tagged with area or any of its sub-areas,
+// overriding a rule set on a parent area.
+func EnableArea(area string) {
+	setAreaRule(area, true)
+}
+
+// DisableArea disables assertions tagged with area or any of its sub-areas.
+// Failures in a disabled area are ignored.
+func DisableArea(area string) {
+	setAreaRule(area, false)
+}
+
+// ResetArea removes the rule set on area, so it inherits from its parent.
+func ResetArea(area string) {
+	areaMu.Lock()
+	defer areaMu.Unlock()
+
+	next := copyAreaRules()
+	delete(next, area)
+	areaRules.Store(&next)
+}
+
+// AreaEnabled reports whether assertions tagged with area are enforced. The
+// most specific rule wins; areas without a rule are enabled.
+func AreaEnabled(area string) bool {
+	rules := areaRules.Load()
+	if rules == nil {
+		return true
+	}
+	for {
+		if on, ok := (*rules)[area]; ok {
+			return on
+		}
+		i := strings.LastIndexByte(area, '.')
+		if i < 0 {
+			return true
+		}
+		area = area[:i]
+	}
+}
+
+// AreaRules returns the rules set with EnableArea and DisableArea, mapping
+// each area to whether it is enabled.
+func AreaRules() map[string]bool {
+	return copyAreaRules()
+}
+
+func setAreaRule(area string, on bool) {
+	areaMu.Lock()
+	defer areaMu.Unlock()
+
+	next := copyAreaRules()
+	next[area] = on
+	areaRules.Store(&next)
+}
+
+func copyAreaRules() map[string]bool {
+	next := map[string]bool{}
+	if rules := areaRules.Load(); rules != nil {
+		for k, v := range *rules {
+			next[k] = v
+		}
+	}
+	return next
+}
+
+// splitArea removes the area pair from data, returning the area and the
+// remaining data.
+func splitArea(data []any) (string, []any) {
+	if v, rest, ok := takeKey(data, AreaKey); ok {
+		if area, ok := v.(string); ok {
+			return area, rest
+		}
+	}
+	return defaultArea, data
+}
+
+// takeKey finds the first pair in data whose key is key, returning its value
+// and a copy of data without the pair.
+func takeKey(data []any, key string) (any, []any, bool) {
+	for i := 0; i+1 < len(data); i += 2 {
+		if k, ok := data[i].(string); ok && k == key {
+			rest := make([]any, 0, len(data)-2)
+			rest = append(rest, data[:i]...)
+			rest = append(rest, data[i+2:]...)
+			return data[i+1], rest, true
+		}
+	}
+	return nil, data, false
+}
@@ -1,129 +1,280 @@
-// // Package myassert provides a simple assertion function for standard Go code,
-// // similar to Python's assert statement.
-// package assert
-
-// import "fmt"
-
-// // Enabled controls whether assertions are active. Set to false to disable.
-// var Enabled = true
-
-// // Assert panics if the condition is false, with an optional formatted message.
-// // If Enabled is false, the assertion is skipped.
-// // Usage: myassert.Assert(x == y, "x should equal y, got %d, want %d", x, y)
-// func Assert(condition bool, format string, args ...any) {
-//     if !Enabled {
-//         return
-//     }
-//     if !condition {
-//         if format == "" {
-//             panic("assertion failed")
-//         }
-//         panic(fmt.Errorf(format, args...))
-//     }
-// }
-
+//go:build !tinygo
 
+// Package assert provides runtime assertions that dump debugging context and
+// terminate the program when an invariant is violated.
 package assert
 
 import (
-	"fmt"
 	"io"
 	"log/slog"
-	"os"
 	"reflect"
-	"runtime/debug"
+	"runtime"
+	"sync"
+	"time"
 )
 
 // TODO using slog for logging
 type AssertData interface {
-    Dump() string
+	Dump() string
 }
 type AssertFlush interface {
-    Flush()
+	Flush()
 }
 
+// dataMu guards flushes and assertData.
+var dataMu sync.RWMutex
 var flushes []AssertFlush = []AssertFlush{}
 var assertData map[string]AssertData = map[string]AssertData{}
-var writer io.Writer
+
+// Enable turns assertion enforcement on. Assertions are enabled by default.
+func Enable() {
+	updateConfig(func(c *config) { c.disabled = false })
+}
+
+// Disable turns assertion enforcement off; every assertion becomes a no-op
+// until Enable is called. It is safe to call concurrently with assertions.
+func Disable() {
+	updateConfig(func(c *config) { c.disabled = true })
+}
+
+// Enabled reports whether assertions are currently enforced.
+func Enabled() bool {
+	return !loadConfig().disabled
+}
 
 func AddAssertData(key string, value AssertData) {
+	dataMu.Lock()
+	defer dataMu.Unlock()
 	assertData[key] = value
 }
 
 func RemoveAssertData(key string) {
+	dataMu.Lock()
+	defer dataMu.Unlock()
 	delete(assertData, key)
 }
 
 func AddAssertFlush(flusher AssertFlush) {
-    flushes = append(flushes, flusher)
+	dataMu.Lock()
+	defer dataMu.Unlock()
+	flushes = append(flushes, flusher)
 }
 
 func ToWriter(w io.Writer) {
-	writer = w
+	updateConfig(func(c *config) {
+		c.writer = w
+		c.journal = nil
+	})
 }
 
 func runAssert(msg string, args ...interface{}) {
-    // There is a bit of a issue here.  if you flush you cannot assert
-    // cannot be reentrant
-    // TODO I am positive i could create some sort of latching that prevents the
-    // reentrant problem
-    for _, f := range flushes {
-        f.Flush()
-    }
-
-    slogValues := []interface{}{
-        "msg",
-        msg,
-        "area",
-        "Assert",
-    }
-    slogValues = append(slogValues, args...)
-    fmt.Fprintf(os.Stderr, "ARGS: %+v\n", args)
-
-	for k, v := range assertData {
-        slogValues = append(slogValues, k, v.Dump())
-	}
-
-    fmt.Fprintf(os.Stderr, "ASSERT\n")
-    for i := 0; i < len(slogValues); i += 2 {
-        fmt.Fprintf(os.Stderr, "   %s=%v\n", slogValues[i], slogValues[i + 1])
-    }
-    fmt.Fprintln(os.Stderr, string(debug.Stack()))
-    os.Exit(1)
-}
-
-// TODO Think about passing around a context for debugging purposes
+	assertIn(msg, args, false)
+}
+
+// warnAssert is runAssert with the failure reported as a warning whatever
+// the mode, for failures found on a goroutine of this package, where a panic
+// could not be recovered and Goexit or exit would hit the wrong code.
+func warnAssert(msg string, args ...any) {
+	assertIn(msg, args, true)
+}
+
+func assertIn(msg string, args []any, warnOnly bool) {
+	c := loadConfig()
+	if c.disabled {
+		return
+	}
+	r := newReport(msg, args)
+	if !AreaEnabled(r.area) {
+		return
+	}
+	m, ok := c.modeFor(r.area, r.severity)
+	if !ok {
+		return
+	}
+	r.mode = m
+
+	r.setSite(failureFrame())
+	if len(c.suppressions) > 0 {
+		if m, ok = c.suppressedMode(r, m); !ok {
+			return
+		}
+		r.mode = m
+	}
+	if warnOnly {
+		m, r.mode = ModeWarn, ModeWarn
+	}
+	if tracking.Load() {
+		trackFailure(r)
+	}
+	deliver := delivered(c, r)
+	notifyObservers(c, r, deliver)
+	if !deliver {
+		return
+	}
+
+	// There is a bit of a issue here.  if you flush you cannot assert
+	// cannot be reentrant
+	// TODO I am positive i could create some sort of latching that prevents the
+	// reentrant problem
+	dataMu.RLock()
+	fs := append([]AssertFlush(nil), flushes...)
+	dataMu.RUnlock()
+	for _, f := range fs {
+		f.Flush()
+	}
+
+	start := time.Now()
+	r.collect(c)
+	if c.breakMode != BreakNever && debugBreak(c, r) {
+		return
+	}
+	if m == ModePanic && insideGuarded() {
+		// Guarded recovers the panic and its caller writes the report, with
+		// whatever context it has, through AssertionError.Report.
+		recordHistory(r)
+		panic(&AssertionError{r: r})
+	}
+	c.write(r)
+	observeReport(start)
+	if m != ModeExit {
+		recordHistory(r)
+	}
+	if c.interactive && m != ModeWarn && promptContinue(r) {
+		return
+	}
+
+	switch m {
+	case ModeWarn:
+		recordWarning(r)
+		return
+	case ModePanic:
+		panic(&AssertionError{r: r, written: true})
+	case ModeGoexit:
+		runtime.Goexit()
+	}
+	exitWithReport(c, r)
+}
+
+// delivered reports whether r is to be reported, rather than kept back by
+// ReportOnce, the circuit breaker or the rate limit.
+func delivered(c *config, r *report) bool {
+	if suppressRepeat(r.mode, r.site) {
+		return false
+	}
+	if r.mode != ModeWarn {
+		return true
+	}
+	suppress, summary := stormBreaker.record(r.time)
+	if summary != "" {
+		io.WriteString(c.outputFor(r.area), summary)
+	}
+	if suppress {
+		return false
+	}
+	ok, n := reportLimiter.allow()
+	r.dropped = n
+	return ok
+}
+
+// The assertions below are written to stay under the inliner's budget: the
+// passing path is a single condition, and everything needed to report a
+// failure, including the Enabled check, lives in functions that are only
+// called on failure. Assertions whose check itself costs something test
+// Enabled first. While TrackSites is on, passing assertions take the same
+// slow path to be recorded.
+
 func Assert(truth bool, msg string, data ...any) {
-	if !truth {
+	if !truth || tracking.Load() {
+		evaluated(!truth, msg, data)
+	}
+}
+
+// evaluated reports a failure, or records a passing assertion's site.
+func evaluated(failed bool, msg string, data []any) {
+	if failed {
 		runAssert(msg, data...)
+	} else {
+		passed(msg, data)
+	}
+}
+
+// AssertAttrs is Assert with slog attributes instead of key/value pairs.
+// Unlike values passed as ...any, attributes built with slog.Int, slog.String
+// and friends are not boxed, so a passing AssertAttrs never allocates, even
+// with data. Prefer it in hot loops.
+func AssertAttrs(truth bool, msg string, attrs ...slog.Attr) {
+	if !truth || tracking.Load() {
+		runAssertAttrs(!truth, msg, attrs)
+	}
+}
+
+func runAssertAttrs(failed bool, msg string, attrs []slog.Attr) {
+	evaluated(failed, msg, attrPairs(attrs))
+}
+
+// attrPairs converts attrs to the key/value pairs used by reports.
+func attrPairs(attrs []slog.Attr) []any {
+	pairs := make([]any, 0, 2*len(attrs))
+	for _, a := range attrs {
+		pairs = append(pairs, a.Key, a.Value.Resolve().Any())
 	}
+	return pairs
 }
 
 func Nil(item any, msg string, data ...any) {
-    slog.Info("Nil Check", "item", item)
-	if item == nil {
-        return
-    }
+	if item != nil || tracking.Load() {
+		nilChecked(item != nil, msg, data)
+	}
+}
 
-    slog.Error("Nil#not nil encountered")
-    runAssert(msg, data...)
+func nilChecked(failed bool, msg string, data []any) {
+	if !failed {
+		passed(msg, data)
+		return
+	}
+	if !Enabled() {
+		return
+	}
+	slog.Error("Nil#not nil encountered")
+	runAssert(msg, data...)
 }
 
 func NotNil(item any, msg string, data ...any) {
-	if item == nil || reflect.ValueOf(item).Kind() == reflect.Ptr && reflect.ValueOf(item).IsNil() {
+	if Enabled() {
+		notNil(item, msg, data)
+	}
+}
+
+// notNil holds the reflection based check so that NotNil itself can be
+// inlined. Callers that know the type should prefer NotNilPtr and friends.
+func notNil(item any, msg string, data []any) {
+	if isNil(item) {
 		slog.Error("NotNil#nil encountered")
 		runAssert(msg, data...)
+	} else if tracking.Load() {
+		passed(msg, data)
 	}
 }
 
+// isNil reports whether item is nil or a nil pointer.
+func isNil(item any) bool {
+	return item == nil || reflect.ValueOf(item).Kind() == reflect.Ptr && reflect.ValueOf(item).IsNil()
+}
+
 func Never(msg string, data ...any) {
-    runAssert(msg, data...)
+	runAssert(msg, data...)
 }
 
 func NoError(err error, msg string, data ...any) {
-	if err != nil {
-        data = append(data, "error", err)
-		runAssert(msg, data...)
+	if err != nil || tracking.Load() {
+		noError(err, msg, data)
 	}
 }
 
+func noError(err error, msg string, data []any) {
+	if err == nil {
+		passed(msg, data)
+		return
+	}
+	runAssert(msg, append(data, "error", err)...)
+}
@@ -0,0 +1,86 @@
+// Package assertage encrypts crash files to age recipients, so reports and
+// traces holding customer data never sit on disk in plaintext. Only holders
+// of a matching identity can read them back. It is a separate module so the
+// assert package itself stays free of dependencies.
+//
+//	// At startup, with the public key of the on-call team:
+//	if err := assertage.EncryptCrashFiles("age1..."); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// In triage tooling, with the private key:
+//	rep, err := assertage.LoadReport("assert-20240102T150405.000-4242.json.age", identityFile)
+package assertage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/bhuvneshuchiha/assert"
+)
+
+// Ext is appended to the names of encrypted crash files.
+const Ext = ".age"
+
+// EncryptCrashFiles makes fatal failures encrypt their crash files to the
+// given recipients, X25519 public keys of the form "age1...". Any one of the
+// matching identities decrypts them.
+func EncryptCrashFiles(recipients ...string) error {
+	if len(recipients) == 0 {
+		return fmt.Errorf("assertage: no recipients")
+	}
+	rs := make([]age.Recipient, len(recipients))
+	for i, s := range recipients {
+		r, err := age.ParseX25519Recipient(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("assertage: %w", err)
+		}
+		rs[i] = r
+	}
+	assert.EncryptCrashFiles(Ext, func(w io.Writer) (io.WriteCloser, error) {
+		return age.Encrypt(w, rs...)
+	})
+	return nil
+}
+
+// Decrypt returns a reader of the plaintext of the encrypted crash file read
+// from r, using the identities in identities, an age identity file such as
+// one written by age-keygen.
+func Decrypt(r io.Reader, identities io.Reader) (io.Reader, error) {
+	ids, err := age.ParseIdentities(identities)
+	if err != nil {
+		return nil, fmt.Errorf("assertage: %w", err)
+	}
+	pr, err := age.Decrypt(r, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("assertage: %w", err)
+	}
+	return pr, nil
+}
+
+// LoadReport reads an encrypted post-mortem file, decrypting it with the
+// identities in the identity file at identityPath.
+func LoadReport(path, identityPath string) (assert.Failure, error) {
+	ids, err := os.Open(identityPath)
+	if err != nil {
+		return assert.Failure{}, err
+	}
+	defer ids.Close()
+	f, err := os.Open(path)
+	if err != nil {
+		return assert.Failure{}, err
+	}
+	defer f.Close()
+	pr, err := Decrypt(f, ids)
+	if err != nil {
+		return assert.Failure{}, err
+	}
+	rep, err := assert.ReadReport(pr)
+	if err != nil {
+		return assert.Failure{}, fmt.Errorf("assertage: reading report %s: %w", path, err)
+	}
+	return rep, nil
+}
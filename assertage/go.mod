@@ -0,0 +1,15 @@
+module github.com/bhuvneshuchiha/assert/assertage
+
+go 1.24.2
+
+require (
+	filippo.io/age v1.2.1
+	github.com/bhuvneshuchiha/assert v0.0.0
+)
+
+require (
+	golang.org/x/crypto v0.24.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
+
+replace github.com/bhuvneshuchiha/assert => ../
@@ -0,0 +1,77 @@
+// Package assertgrpc provides gRPC server interceptors that turn assertion
+// failures into codes.Internal errors instead of crashing the server. It is a
+// separate module so the assert package itself stays free of dependencies.
+package assertgrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bhuvneshuchiha/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RedactedMetadata lists incoming metadata keys whose values are replaced
+// with "[redacted]" in reports. Binary ("-bin") keys are always omitted.
+var RedactedMetadata = []string{"authorization", "cookie", "proxy-authorization"}
+
+// UnaryServerInterceptor recovers assertion failures raised by unary
+// handlers, writes the failure's report with the method, peer and incoming
+// metadata attached, and returns a codes.Internal error. Like
+// asserthttp.Middleware it needs assert.ModePanic.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		failure := assert.Guarded(func() { resp, err = handler(ctx, req) })
+		if failure == nil {
+			return resp, err
+		}
+		failure.Report(rpcData(ctx, info.FullMethod)...)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		failure := assert.Guarded(func() { err = handler(srv, ss) })
+		if failure == nil {
+			return err
+		}
+		failure.Report(rpcData(ss.Context(), info.FullMethod)...)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func rpcData(ctx context.Context, method string) []any {
+	data := []any{"grpc.method", method}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		data = append(data, "grpc.peer", p.Addr.String())
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for k, vs := range md {
+			if strings.HasSuffix(k, "-bin") {
+				continue
+			}
+			v := strings.Join(vs, ",")
+			if redacted(k) {
+				v = "[redacted]"
+			}
+			data = append(data, "grpc.md."+k, v)
+		}
+	}
+	return data
+}
+
+func redacted(key string) bool {
+	for _, r := range RedactedMetadata {
+		if strings.EqualFold(key, r) {
+			return true
+		}
+	}
+	return false
+}
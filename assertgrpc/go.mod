@@ -0,0 +1,18 @@
+module github.com/bhuvneshuchiha/assert/assertgrpc
+
+go 1.24.2
+
+require (
+	github.com/bhuvneshuchiha/assert v0.0.0
+	google.golang.org/grpc v1.72.2
+)
+
+require (
+	golang.org/x/net v0.50.0 // indirect
+	golang.org/x/sys v0.41.0 // indirect
+	golang.org/x/text v0.34.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+)
+
+replace github.com/bhuvneshuchiha/assert => ../
@@ -0,0 +1,144 @@
+//go:build !tinygo
+
+package asserthttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bhuvneshuchiha/assert"
+)
+
+type adminState struct {
+	Enabled     bool            `json:"enabled"`
+	Mode        string          `json:"mode"`
+	MinSeverity string          `json:"min_severity"`
+	Areas       map[string]bool `json:"areas"`
+	Sites       []assert.Site   `json:"sites,omitempty"`
+}
+
+// AdminHandler returns a handler for inspecting and changing assertion
+// behavior at runtime, meant to be mounted under /debug/assert. It has no
+// authentication of its own, so it must be mounted behind the service's
+// operator authentication or on a listener only operators can reach.
+//
+// GET responds with the configuration, area rules and, while
+// assert.TrackSites is on, site statistics as JSON. POST changes the
+// configuration with form values and responds with the new state:
+//
+//	enabled=true|false
+//	mode=exit|panic|warn
+//	min_severity=debug|warn|error|fatal
+//	area=<name>&area_enabled=true|false|reset
+//
+// POSTs a browser sends from another origin are rejected with 403, so a page
+// an operator visits cannot change the configuration through their
+// session. Browsers mark requests with Sec-Fetch-Site, or else Origin;
+// requests with neither, such as those made with curl, are not from a
+// browser and are accepted.
+func AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPost:
+			if !sameOrigin(r) {
+				http.Error(w, "cross-origin request rejected", http.StatusForbidden)
+				return
+			}
+			if err := applyAdmin(r); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(adminState{
+			Enabled:     assert.Enabled(),
+			Mode:        assert.CurrentMode().String(),
+			MinSeverity: assert.MinSeverity().String(),
+			Areas:       assert.AreaRules(),
+			Sites:       assert.Sites(),
+		})
+	})
+}
+
+// sameOrigin reports whether r did not come from a page on another origin.
+func sameOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	case "":
+	default:
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// applyAdmin validates every change in r before applying any of them.
+func applyAdmin(r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	var changes []func()
+
+	if v := r.PostForm.Get("enabled"); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("enabled: %w", err)
+		}
+		if on {
+			changes = append(changes, assert.Enable)
+		} else {
+			changes = append(changes, assert.Disable)
+		}
+	}
+	if v := r.PostForm.Get("mode"); v != "" {
+		m, err := assert.ParseMode(v)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, func() { assert.SetMode(m) })
+	}
+	if v := r.PostForm.Get("min_severity"); v != "" {
+		s, err := assert.ParseSeverity(v)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, func() { assert.SetMinSeverity(s) })
+	}
+	if area := r.PostForm.Get("area"); area != "" {
+		switch v := r.PostForm.Get("area_enabled"); v {
+		case "reset":
+			changes = append(changes, func() { assert.ResetArea(area) })
+		default:
+			on, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("area_enabled: %w", err)
+			}
+			if on {
+				changes = append(changes, func() { assert.EnableArea(area) })
+			} else {
+				changes = append(changes, func() { assert.DisableArea(area) })
+			}
+		}
+	}
+
+	for _, change := range changes {
+		change()
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+//go:build !tinygo
+
+// Package asserthttp connects assertions to net/http servers.
+package asserthttp
+
+import (
+	"net/http"
+
+	"github.com/bhuvneshuchiha/assert"
+)
+
+// RequestIDHeader is the request header whose value, if present, is added to
+// reports as http.request_id.
+var RequestIDHeader = "X-Request-Id"
+
+// Middleware recovers assertion failures raised while next serves a request,
+// writes the failure's report with the request's method, URL, remote address
+// and request ID attached, and responds with 500 Internal Server Error
+// unless next has already started the response. One bad request then costs
+// a full report instead of the whole server.
+//
+// Recovery needs assert.ModePanic; in the other modes failures behave as
+// they do elsewhere. Panics that are not assertion failures pass through.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tw := &trackingWriter{ResponseWriter: w}
+		failure := assert.Guarded(func() { next.ServeHTTP(tw, r) })
+		if failure == nil {
+			return
+		}
+		failure.Report(requestData(r)...)
+		if !tw.started {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	})
+}
+
+// trackingWriter records whether the response has been started, after which
+// its status can no longer be changed.
+type trackingWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *trackingWriter) WriteHeader(code int) {
+	// Informational responses leave the status to be set.
+	if code >= 200 {
+		w.started = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush keeps http.Flusher working through the wrapper.
+func (w *trackingWriter) Flush() {
+	w.started = true
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func requestData(r *http.Request) []any {
+	data := []any{
+		"http.method", r.Method,
+		"http.url", r.URL.String(),
+		"http.remote", r.RemoteAddr,
+	}
+	if id := r.Header.Get(RequestIDHeader); id != "" {
+		data = append(data, "http.request_id", id)
+	}
+	return data
+}
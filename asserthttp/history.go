@@ -0,0 +1,67 @@
+//go:build !tinygo
+
+package asserthttp
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bhuvneshuchiha/assert"
+)
+
+var historyPage = template.Must(template.New("history").Funcs(template.FuncMap{
+	"even": func(i int) bool { return i%2 == 0 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Assertion failures</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+td.data { font-family: monospace; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Assertion failures</h1>
+<p>{{len .}} recent failures, newest first.</p>
+<table>
+<tr><th>Time</th><th>Severity</th><th>Area</th><th>Message</th><th>Site</th><th>Data</th></tr>
+{{range .}}<tr>
+<td>{{.Time.Format "2006-01-02 15:04:05.000"}}</td>
+<td>{{.Severity}}</td>
+<td>{{.Area}}</td>
+<td>{{.Message}}</td>
+<td title="{{.Function}}">{{.File}}:{{.Line}}</td>
+<td class="data">{{range $i, $v := .Data}}{{if even $i}}{{printf "%v" $v}}={{else}}{{printf "%v" $v}}
+{{end}}{{end}}</td>
+</tr>{{end}}
+</table>
+</body>
+</html>
+`))
+
+// HistoryHandler returns a handler serving assert.History, meant to be
+// mounted at /debug/assert/history. Browsers get an HTML table, newest
+// failure first; clients that accept application/json, or pass
+// ?format=json, get the failures as a JSON array, oldest first. Like
+// AdminHandler it must only be reachable by operators.
+func HistoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failures := assert.History()
+		if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			enc.Encode(failures)
+			return
+		}
+		slices.Reverse(failures)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		historyPage.Execute(w, failures)
+	})
+}
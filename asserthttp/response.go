@@ -0,0 +1,91 @@
+//go:build !tinygo
+
+package asserthttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bhuvneshuchiha/assert"
+)
+
+// MaxBodyExcerpt bounds how much of a response body the response assertions
+// include in their reports.
+var MaxBodyExcerpt = 1024
+
+// Status asserts that resp has status code want, for internal clients where
+// any other answer from a dependency is a broken invariant. The report
+// includes the request's method and URL without its query, the status and
+// an excerpt of the body; the excerpt is read from resp.Body and put back,
+// so the caller can still read the whole body.
+func Status(resp *http.Response, want int, msg string, data ...any) {
+	if assert.Enabled() && resp.StatusCode != want {
+		assert.Never(msg, append(data, responseData(resp, "http.want_status", want)...)...)
+	}
+}
+
+// Success asserts that resp has a 2xx status code.
+func Success(resp *http.Response, msg string, data ...any) {
+	if assert.Enabled() && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		assert.Never(msg, append(data, responseData(resp, "http.want_status", "2xx")...)...)
+	}
+}
+
+// Header asserts that resp's header key has the value want.
+func Header(resp *http.Response, key, want, msg string, data ...any) {
+	if got := resp.Header.Get(key); assert.Enabled() && got != want {
+		assert.Never(msg, append(data, responseData(resp,
+			"http.header", key,
+			"http.want_header", want,
+			"http.got_header", got,
+		)...)...)
+	}
+}
+
+// responseData describes resp for a report, after the given pairs.
+func responseData(resp *http.Response, pairs ...any) []any {
+	data := append(pairs, "http.status", resp.Status)
+	if req := resp.Request; req != nil {
+		// The query often carries credentials, so it is left out.
+		u := *req.URL
+		u.RawQuery, u.ForceQuery = "", false
+		data = append(data, "http.method", req.Method, "http.url", u.Redacted())
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		data = append(data, "http.content_type", ct)
+	}
+	if excerpt := bodyExcerpt(resp); excerpt != "" {
+		data = append(data, "http.body", excerpt)
+	}
+	return data
+}
+
+// bodyExcerpt reads up to MaxBodyExcerpt bytes of resp's body and replaces
+// resp.Body with one that returns them again.
+func bodyExcerpt(resp *http.Response) string {
+	if resp.Body == nil || resp.Body == http.NoBody || MaxBodyExcerpt <= 0 {
+		return ""
+	}
+	buf := make([]byte, MaxBodyExcerpt+1)
+	n, _ := io.ReadFull(resp.Body, buf)
+	buf = buf[:n]
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), resp.Body), resp.Body}
+
+	if n == 0 {
+		return ""
+	}
+	if bytes.IndexByte(buf, 0) >= 0 {
+		return "[binary, " + strconv.Itoa(n) + " bytes read]"
+	}
+	excerpt := strings.TrimSpace(strings.ToValidUTF8(string(buf[:min(n, MaxBodyExcerpt)]), ""))
+	if n > MaxBodyExcerpt {
+		excerpt += " [truncated]"
+	}
+	return excerpt
+}
@@ -0,0 +1,104 @@
+//go:build !tinygo
+
+package asserthttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/bhuvneshuchiha/assert"
+)
+
+// maxSettingsSize bounds the settings documents PollSettings accepts.
+const maxSettingsSize = 1 << 20
+
+// PollSettings fetches assertion settings from url, a JSON document as
+// described by assert.Settings, and applies them with assert.ApplySettings,
+// then fetches them again every interval until ctx is done. A fleet's
+// assertion posture can then be changed centrally without restarts:
+//
+//	if err := asserthttp.PollSettings(ctx, "https://config.internal/assert/payments.json", time.Minute); err != nil {
+//		log.Printf("using local assertion settings: %v", err)
+//	}
+//
+// The first fetch happens before PollSettings returns, and its error is
+// returned; polling continues either way. A non-positive interval is an
+// error, and nothing is fetched. Later errors are logged with slog
+// and leave the settings in effect unchanged. Servers that send an ETag are
+// asked for the document only when it changed.
+//
+// Documents that set the output or routes are rejected, since those name
+// files on the local host, which a remote document must not choose.
+func PollSettings(ctx context.Context, url string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("asserthttp: non-positive settings poll interval %v", interval)
+	}
+	p := &settingsPoller{url: url, client: &http.Client{Timeout: WebhookTimeout}}
+	err := p.poll(ctx)
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if err := p.poll(ctx); err != nil && ctx.Err() == nil {
+					slog.Warn("asserthttp: polling settings", "url", url, "error", err)
+				}
+			}
+		}
+	}()
+	return err
+}
+
+type settingsPoller struct {
+	url    string
+	client *http.Client
+	etag   string
+}
+
+func (p *settingsPoller) poll(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return nil
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("asserthttp: settings %s: %s", p.url, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSettingsSize+1))
+	if err != nil {
+		return err
+	}
+	if len(b) > maxSettingsSize {
+		return fmt.Errorf("asserthttp: settings %s larger than %d bytes", p.url, maxSettingsSize)
+	}
+	s, err := assert.ParseSettings(b)
+	if err != nil {
+		return err
+	}
+	if s.Output != nil || s.Routes != nil {
+		return fmt.Errorf("asserthttp: settings %s: output and routes cannot be set remotely", p.url)
+	}
+	if err := assert.ApplySettings(s); err != nil {
+		return err
+	}
+	p.etag = resp.Header.Get("ETag")
+	return nil
+}
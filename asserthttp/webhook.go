@@ -0,0 +1,125 @@
+//go:build !tinygo
+
+package asserthttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+	"unicode/utf8"
+)
+
+// WebhookTimeout bounds each delivery made by a Webhook.
+var WebhookTimeout = 5 * time.Second
+
+// WebhookCompressAbove is the size in bytes from which a Webhook gzips
+// reports and sends them with "Content-Encoding: gzip". Zero, the default,
+// never compresses, since not every receiver accepts compressed requests.
+var WebhookCompressAbove = 0
+
+// WebhookQueueSize is how many reports a Webhook holds while earlier ones
+// are being delivered. Reports written while the queue is full are dropped.
+var WebhookQueueSize = 64
+
+// Webhook returns a writer that POSTs each report written to it to url, for
+// use with assert.ToWriter or assert.Route. Reports rendered with
+// assert.FormatJSON are sent as application/json, those rendered with
+// assert.FormatBinary as application/octet-stream and others as text/plain.
+//
+// Reports are delivered in order by a background goroutine, so a slow
+// receiver does not hold up the failing code. Write returns an error only
+// when the queue is full and the report is dropped; deliveries that fail or
+// are answered with a status other than 2xx are logged with slog and not
+// retried. The writer's Flush method waits for the reports written so far to
+// be delivered, which fatal failures do before exiting.
+func Webhook(url string) io.Writer {
+	h := &webhook{
+		url:    url,
+		client: &http.Client{Timeout: WebhookTimeout},
+		queue:  make(chan delivery, WebhookQueueSize),
+	}
+	go h.run()
+	return h
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+	queue  chan delivery
+}
+
+// delivery is a report to send, or, with done set, a Flush waiting for the
+// reports before it.
+type delivery struct {
+	report []byte
+	done   chan struct{}
+}
+
+func (h *webhook) Write(p []byte) (int, error) {
+	select {
+	case h.queue <- delivery{report: bytes.Clone(p)}:
+		return len(p), nil
+	default:
+		return 0, fmt.Errorf("asserthttp: webhook %s: queue full, report dropped", h.url)
+	}
+}
+
+// Flush waits until the reports written before it have been delivered.
+func (h *webhook) Flush() {
+	done := make(chan struct{})
+	h.queue <- delivery{done: done}
+	<-done
+}
+
+func (h *webhook) run() {
+	for d := range h.queue {
+		if d.done != nil {
+			close(d.done)
+			continue
+		}
+		if err := h.send(d.report); err != nil {
+			slog.Warn("asserthttp: delivering report", "url", h.url, "error", err)
+		}
+	}
+}
+
+func (h *webhook) send(p []byte) error {
+	contentType := "text/plain; charset=utf-8"
+	switch {
+	case !utf8.Valid(p):
+		contentType = "application/octet-stream"
+	case bytes.HasPrefix(bytes.TrimSpace(p), []byte("{")):
+		contentType = "application/json"
+	}
+	body := p
+	compress := WebhookCompressAbove > 0 && len(p) >= WebhookCompressAbove
+	if compress {
+		var b bytes.Buffer
+		zw := gzip.NewWriter(&b)
+		zw.Write(p)
+		zw.Close()
+		body = b.Bytes()
+	}
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("asserthttp: webhook %s: %s", h.url, resp.Status)
+	}
+	return nil
+}
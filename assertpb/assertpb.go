@@ -0,0 +1,218 @@
+// Package assertpb provides assertions over protobuf messages. It compares
+// with proto semantics rather than reflect.DeepEqual, which is wrong for
+// generated messages, and reports differences by field path. It is a
+// separate module so the assert package itself stays free of dependencies.
+package assertpb
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bhuvneshuchiha/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MaxDiffs bounds the number of differing fields listed in a report.
+var MaxDiffs = 20
+
+// Equal asserts that got and want are equal as proto.Equal defines it:
+// unknown fields are compared byte for byte and NaN equals NaN. The report
+// lists the differing fields by path:
+//
+//	diff=order.items[2].quantity: got 3, want 4
+func Equal(got, want proto.Message, msg string, data ...any) {
+	if !assert.Enabled() || proto.Equal(got, want) {
+		return
+	}
+	assert.Never(msg, append(data, "diff", Diff(got, want))...)
+}
+
+// Diff describes the differences between got and want, one field path per
+// line, or returns "" if they are equal.
+func Diff(got, want proto.Message) string {
+	if proto.Equal(got, want) {
+		return ""
+	}
+	g, w := messageOf(got), messageOf(want)
+	if g == nil || w == nil {
+		return fmt.Sprintf("got %v, want %v", describe(g), describe(w))
+	}
+	if g.Descriptor().FullName() != w.Descriptor().FullName() {
+		return fmt.Sprintf("got %s, want %s", g.Descriptor().FullName(), w.Descriptor().FullName())
+	}
+	d := &differ{}
+	d.message(string(g.Descriptor().Name()), g, w)
+	if d.more > 0 {
+		d.lines = append(d.lines, fmt.Sprintf("... and %d more", d.more))
+	}
+	return strings.Join(d.lines, "\n")
+}
+
+func messageOf(m proto.Message) protoreflect.Message {
+	if m == nil {
+		return nil
+	}
+	r := m.ProtoReflect()
+	if !r.IsValid() {
+		return nil
+	}
+	return r
+}
+
+func describe(m protoreflect.Message) string {
+	if m == nil {
+		return "nil"
+	}
+	return string(m.Descriptor().FullName())
+}
+
+type differ struct {
+	lines []string
+	more  int
+}
+
+func (d *differ) add(path string, got, want any) {
+	if len(d.lines) >= MaxDiffs {
+		d.more++
+		return
+	}
+	d.lines = append(d.lines, fmt.Sprintf("%s: got %s, want %s", path, format(got), format(want)))
+}
+
+func (d *differ) message(path string, got, want protoreflect.Message) {
+	fields := map[protoreflect.FieldNumber]protoreflect.FieldDescriptor{}
+	collect := func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields[fd.Number()] = fd
+		return true
+	}
+	got.Range(collect)
+	want.Range(collect)
+	numbers := make([]protoreflect.FieldNumber, 0, len(fields))
+	for n := range fields {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	for _, n := range numbers {
+		fd := fields[n]
+		name := string(fd.Name())
+		if fd.IsExtension() {
+			name = "[" + string(fd.FullName()) + "]"
+		}
+		p := path + "." + name
+		switch {
+		case !got.Has(fd):
+			d.add(p, nil, want.Get(fd))
+		case !want.Has(fd):
+			d.add(p, got.Get(fd), nil)
+		case fd.IsList():
+			d.list(p, fd, got.Get(fd).List(), want.Get(fd).List())
+		case fd.IsMap():
+			d.mapField(p, fd, got.Get(fd).Map(), want.Get(fd).Map())
+		default:
+			d.value(p, fd, got.Get(fd), want.Get(fd))
+		}
+	}
+	if gu, wu := got.GetUnknown(), want.GetUnknown(); !bytes.Equal(gu, wu) {
+		d.add(path+".<unknown fields>", fmt.Sprintf("%d bytes", len(gu)), fmt.Sprintf("%d bytes", len(wu)))
+	}
+}
+
+func (d *differ) list(path string, fd protoreflect.FieldDescriptor, got, want protoreflect.List) {
+	for i := 0; i < got.Len() || i < want.Len(); i++ {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= want.Len():
+			d.add(p, got.Get(i), nil)
+		case i >= got.Len():
+			d.add(p, nil, want.Get(i))
+		default:
+			d.value(p, fd, got.Get(i), want.Get(i))
+		}
+	}
+}
+
+func (d *differ) mapField(path string, fd protoreflect.FieldDescriptor, got, want protoreflect.Map) {
+	keys := map[string]protoreflect.MapKey{}
+	collect := func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys[k.String()] = k
+		return true
+	}
+	got.Range(collect)
+	want.Range(collect)
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		k := keys[name]
+		p := fmt.Sprintf("%s[%q]", path, name)
+		switch {
+		case !want.Has(k):
+			d.add(p, got.Get(k), nil)
+		case !got.Has(k):
+			d.add(p, nil, want.Get(k))
+		default:
+			d.value(p, fd.MapValue(), got.Get(k), want.Get(k))
+		}
+	}
+}
+
+// value compares a singular value, or an element of a list or map, of the
+// kind described by fd.
+func (d *differ) value(path string, fd protoreflect.FieldDescriptor, got, want protoreflect.Value) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		d.message(path, got.Message(), want.Message())
+	case protoreflect.BytesKind:
+		if !bytes.Equal(got.Bytes(), want.Bytes()) {
+			d.add(path, got, want)
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		g, w := got.Float(), want.Float()
+		if g != w && !(math.IsNaN(g) && math.IsNaN(w)) {
+			d.add(path, got, want)
+		}
+	case protoreflect.EnumKind:
+		if got.Enum() != want.Enum() {
+			d.add(path, enumName(fd, got.Enum()), enumName(fd, want.Enum()))
+		}
+	default:
+		if !got.Equal(want) {
+			d.add(path, got, want)
+		}
+	}
+}
+
+func enumName(fd protoreflect.FieldDescriptor, n protoreflect.EnumNumber) string {
+	if v := fd.Enum().Values().ByNumber(n); v != nil {
+		return string(v.Name())
+	}
+	return fmt.Sprint(int32(n))
+}
+
+func format(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "<unset>"
+	case protoreflect.Value:
+		switch x := v.Interface().(type) {
+		case protoreflect.Message:
+			return "{" + string(x.Descriptor().Name()) + "}"
+		case string:
+			return fmt.Sprintf("%q", x)
+		case []byte:
+			return fmt.Sprintf("%x", x)
+		case protoreflect.EnumNumber:
+			return fmt.Sprint(int32(x))
+		}
+		return fmt.Sprint(v.Interface())
+	}
+	return fmt.Sprint(v)
+}
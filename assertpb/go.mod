@@ -0,0 +1,10 @@
+module github.com/bhuvneshuchiha/assert/assertpb
+
+go 1.24.2
+
+require (
+	github.com/bhuvneshuchiha/assert v0.0.0
+	google.golang.org/protobuf v1.36.11
+)
+
+replace github.com/bhuvneshuchiha/assert => ../
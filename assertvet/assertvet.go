@@ -0,0 +1,189 @@
+// Package assertvet defines an analyzer that checks calls into the assert
+// package for mistakes that would otherwise only surface when an assertion
+// fails:
+//
+//   - key/value data lists of odd length
+//   - keys that are not strings
+//   - conditions that are constant true, so the assertion can never fail
+//   - Nil and NotNil arguments whose static type makes the check wrong, such
+//     as a nil *T passed to Nil, which fails because the interface holding
+//     it is not nil
+package assertvet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const assertPath = "github.com/bhuvneshuchiha/assert"
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "assertvet",
+	Doc:      "check calls to the assert package for malformed data and impossible conditions",
+	URL:      "https://pkg.go.dev/github.com/bhuvneshuchiha/assert/assertvet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != assertPath {
+			return
+		}
+		sig := fn.Type().(*types.Signature)
+		checkData(pass, call, sig)
+		checkCondition(pass, call, fn, sig)
+		checkNil(pass, call, fn, sig)
+	})
+	return nil, nil
+}
+
+// checkData checks the key/value pairs passed as the final data ...any
+// parameter.
+func checkData(pass *analysis.Pass, call *ast.CallExpr, sig *types.Signature) {
+	params := sig.Params()
+	if !sig.Variadic() || call.Ellipsis.IsValid() || params.At(params.Len()-1).Name() != "data" {
+		return
+	}
+	first := params.Len() - 1
+	if len(call.Args) <= first {
+		return
+	}
+	// A *assert.Message, *assert.Metadata or assert.EventuallyOption stands
+	// alone rather than in a pair.
+	var data []ast.Expr
+	for _, arg := range call.Args[first:] {
+		if !isStandalone(pass.TypesInfo.TypeOf(arg)) {
+			data = append(data, arg)
+		}
+	}
+	if len(data)%2 != 0 {
+		pass.Reportf(data[len(data)-1].Pos(), "odd number of data arguments: the last value has no key")
+	}
+	for i := 0; i < len(data); i += 2 {
+		t := pass.TypesInfo.TypeOf(data[i])
+		if t == nil {
+			continue
+		}
+		if b, ok := t.Underlying().(*types.Basic); !ok || b.Info()&types.IsString == 0 {
+			pass.Reportf(data[i].Pos(), "data key of type %s is not a string", typeName(pass, t))
+		}
+	}
+}
+
+// isStandalone reports whether t is *assert.Message, *assert.Metadata or
+// assert.EventuallyOption.
+func isStandalone(t types.Type) bool {
+	ptr, isPtr := t.(*types.Pointer)
+	if isPtr {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != assertPath {
+		return false
+	}
+	switch obj.Name() {
+	case "Message", "Metadata":
+		return isPtr
+	case "EventuallyOption":
+		return !isPtr
+	}
+	return false
+}
+
+// checkCondition reports bool conditions that are constant true.
+func checkCondition(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, sig *types.Signature) {
+	params := sig.Params()
+	for i := 0; i < params.Len() && i < len(call.Args); i++ {
+		p := params.At(i)
+		if p.Name() != "truth" && p.Name() != "cond" {
+			continue
+		}
+		if b, ok := p.Type().(*types.Basic); !ok || b.Kind() != types.Bool {
+			continue
+		}
+		tv := pass.TypesInfo.Types[call.Args[i]]
+		if tv.Value != nil && tv.Value.Kind() == constant.Bool && constant.BoolVal(tv.Value) {
+			pass.Reportf(call.Args[i].Pos(), "condition of %s is always true, so it can never fail", fn.Name())
+		}
+	}
+}
+
+// nilChecks names the functions whose item parameter is checked for nil
+// through an interface, and whether they expect it to be nil.
+var nilChecks = map[string]bool{
+	"Nil":         true,
+	"NilCtx":      true,
+	"CheckNil":    true,
+	"NotNil":      false,
+	"NotNilCtx":   false,
+	"CheckNotNil": false,
+}
+
+// checkNil reports Nil and NotNil arguments whose static type makes the
+// check wrong.
+func checkNil(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, sig *types.Signature) {
+	wantNil, ok := nilChecks[fn.Name()]
+	if !ok || sig.Recv() != nil {
+		return
+	}
+	var item ast.Expr
+	params := sig.Params()
+	for i := 0; i < params.Len() && i < len(call.Args); i++ {
+		if params.At(i).Name() == "item" {
+			item = call.Args[i]
+		}
+	}
+	if item == nil {
+		return
+	}
+	tv := pass.TypesInfo.Types[item]
+	if tv.Type == nil || tv.IsNil() || types.IsInterface(tv.Type) {
+		return
+	}
+
+	switch u := tv.Type.Underlying().(type) {
+	case *types.Pointer:
+		if wantNil {
+			pass.Reportf(item.Pos(), "%s with an argument of type %s fails even when it is nil, because the interface holding it is not; compare with nil and use Assert", fn.Name(), typeName(pass, tv.Type))
+		}
+	case *types.Map, *types.Slice, *types.Chan, *types.Signature:
+		if wantNil {
+			pass.Reportf(item.Pos(), "%s with an argument of type %s fails even when it is nil, because the interface holding it is not; compare with nil and use Assert", fn.Name(), typeName(pass, tv.Type))
+		} else {
+			pass.Reportf(item.Pos(), "%s does not detect a nil %s; use the typed NotNil variant", fn.Name(), typeName(pass, tv.Type))
+		}
+	case *types.Basic:
+		if u.Kind() == types.UnsafePointer {
+			return
+		}
+		nonNilable(pass, item, fn, tv.Type, wantNil)
+	default:
+		nonNilable(pass, item, fn, tv.Type, wantNil)
+	}
+}
+
+func nonNilable(pass *analysis.Pass, item ast.Expr, fn *types.Func, t types.Type, wantNil bool) {
+	if wantNil {
+		pass.Reportf(item.Pos(), "%s with an argument of type %s always fails: it can never be nil", fn.Name(), typeName(pass, t))
+	} else {
+		pass.Reportf(item.Pos(), "%s with an argument of type %s never fails: it can never be nil", fn.Name(), typeName(pass, t))
+	}
+}
+
+func typeName(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(pass.Pkg))
+}
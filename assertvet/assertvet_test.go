@@ -0,0 +1,12 @@
+package assertvet_test
+
+import (
+	"testing"
+
+	"github.com/bhuvneshuchiha/assert/assertvet"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), assertvet.Analyzer, "a")
+}
@@ -0,0 +1,15 @@
+// Command assertvet checks calls to the assert package. Run it on its own or
+// through go vet:
+//
+//	go install github.com/bhuvneshuchiha/assert/assertvet/cmd/assertvet@latest
+//	go vet -vettool=$(which assertvet) ./...
+package main
+
+import (
+	"github.com/bhuvneshuchiha/assert/assertvet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(assertvet.Analyzer)
+}
@@ -0,0 +1,52 @@
+package a
+
+import (
+	"time"
+
+	"github.com/bhuvneshuchiha/assert"
+)
+
+type key string
+
+var meta = assert.Meta("owner", "storage")
+
+func data(n int, k key, args []any) {
+	assert.Assert(n > 0, "ok", "n", n)
+	assert.Assert(n > 0, "odd", "n", n, "m") // want `odd number of data arguments: the last value has no key`
+	assert.Assert(n > 0, "odd", n)           // want `odd number of data arguments` `data key of type int is not a string`
+	assert.Assert(n > 0, "named key", k, n)
+	assert.Assert(n > 0, "int key", 1, n) // want `data key of type int is not a string`
+	assert.Assert(n > 0, "spread", args...)
+	assert.Never("none")
+}
+
+func standalones(n int) {
+	assert.Assert(n > 0, "message", assert.Msg("m"), "n", n)
+	assert.Assert(n > 0, "metadata", meta, "n", n)
+	assert.Assert(n > 0, "both", meta, assert.Msg("m"))
+	assert.Eventually(func() bool { return n > 0 }, time.Second, "option", assert.Interval(time.Millisecond), "n", n)
+	assert.Assert(n > 0, "message value", *assert.Msg("m"), "n") // want `data key of type github.com/bhuvneshuchiha/assert.Message is not a string`
+}
+
+func conditions(n int) {
+	assert.Assert(true, "always") // want `condition of Assert is always true, so it can never fail`
+	assert.Assert(false, "never true")
+	assert.Assert(n == n, "not constant")
+}
+
+type T struct{}
+
+func nils(p *T, m map[string]int, s []int, c chan int, f func(), v T, i int, e error) {
+	assert.Nil(p, "ptr")             // want `Nil with an argument of type \*T fails even when it is nil`
+	assert.Nil(m, "map")             // want `Nil with an argument of type map\[string\]int fails even when it is nil`
+	assert.NotNil(s, "slice")        // want `NotNil does not detect a nil \[\]int; use the typed NotNil variant`
+	assert.NotNil(c, "chan")         // want `NotNil does not detect a nil chan int`
+	assert.NotNil(f, "func")         // want `NotNil does not detect a nil func\(\)`
+	assert.NotNil(p, "ptr")          // pointers are detected
+	assert.Nil(v, "struct")          // want `Nil with an argument of type T always fails: it can never be nil`
+	assert.NotNil(i, "int")          // want `NotNil with an argument of type int never fails: it can never be nil`
+	assert.Nil(e, "interface")       // interfaces are checked as they are
+	assert.Nil(nil, "untyped")       // untyped nil
+	_ = assert.CheckNil(p, "ptr")    // want `CheckNil with an argument of type \*T fails even when it is nil`
+	_ = assert.CheckNotNil(m, "map") // want `CheckNotNil does not detect a nil map\[string\]int`
+}
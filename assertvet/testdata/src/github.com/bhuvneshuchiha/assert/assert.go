@@ -0,0 +1,24 @@
+// Package assert is a stub of the functions and types the analyzer knows.
+package assert
+
+import "time"
+
+func Assert(truth bool, msg string, data ...any)                                  {}
+func Nil(item any, msg string, data ...any)                                       {}
+func NotNil(item any, msg string, data ...any)                                    {}
+func CheckNil(item any, msg string, data ...any) error                            { return nil }
+func CheckNotNil(item any, msg string, data ...any) error                         { return nil }
+func Never(msg string, data ...any)                                               {}
+func Eventually(cond func() bool, timeout time.Duration, msg string, data ...any) {}
+
+type Message struct{}
+
+func Msg(text string) *Message { return &Message{} }
+
+type Metadata struct{}
+
+func Meta(kv ...string) *Metadata { return &Metadata{} }
+
+type EventuallyOption func()
+
+func Interval(d time.Duration) EventuallyOption { return nil }
@@ -0,0 +1,196 @@
+// Package assertyaml provides assertions over YAML documents, for services
+// that check rendered configuration at startup. It is a separate module so
+// the assert package itself stays free of dependencies.
+package assertyaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bhuvneshuchiha/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// MaxDiffs bounds the number of differences listed in a report.
+var MaxDiffs = 20
+
+// Valid asserts that b parses as a stream of YAML documents. The report
+// includes the parser's error, which names the offending line.
+func Valid(b []byte, msg string, data ...any) {
+	if !assert.Enabled() {
+		return
+	}
+	if _, err := decode(b); err != nil {
+		assert.Never(msg, append(data, "yaml.error", err)...)
+	}
+}
+
+// Equal asserts that got and want hold the same data, ignoring formatting,
+// comments, key order and the choice between equivalent scalar styles.
+// Anchors and aliases are expanded before comparing. The report lists the
+// differences by path:
+//
+//	diff=$.server.ports[1]: got 8443 (int), want 9443 (int)
+func Equal(got, want []byte, msg string, data ...any) {
+	if !assert.Enabled() {
+		return
+	}
+	g, err := decode(got)
+	if err != nil {
+		assert.Never(msg, append(data, "yaml.error", fmt.Errorf("got: %w", err))...)
+		return
+	}
+	w, err := decode(want)
+	if err != nil {
+		assert.Never(msg, append(data, "yaml.error", fmt.Errorf("want: %w", err))...)
+		return
+	}
+	if reflect.DeepEqual(g, w) {
+		return
+	}
+	d := &differ{}
+	if len(g) == 1 && len(w) == 1 {
+		d.value("$", g[0], w[0])
+	} else {
+		d.value("$", g, w)
+	}
+	assert.Never(msg, append(data, "diff", d.String())...)
+}
+
+// decode parses every document in b.
+func decode(b []byte) ([]any, error) {
+	var docs []any
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var doc any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, normalize(doc))
+	}
+}
+
+// normalize converts the map[any]any yaml.v3 produces for mappings with
+// non-string keys to map[string]any, so that mappings compare alike.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = normalize(e)
+		}
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = normalize(e)
+		}
+	}
+	return v
+}
+
+type differ struct {
+	lines []string
+	more  int
+}
+
+func (d *differ) String() string {
+	lines := d.lines
+	if d.more > 0 {
+		lines = append(lines, fmt.Sprintf("... and %d more", d.more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (d *differ) add(path string, got, want any) {
+	if len(d.lines) >= MaxDiffs {
+		d.more++
+		return
+	}
+	d.lines = append(d.lines, fmt.Sprintf("%s: got %s, want %s", path, format(got), format(want)))
+}
+
+func (d *differ) value(path string, got, want any) {
+	switch g := got.(type) {
+	case map[string]any:
+		if w, ok := want.(map[string]any); ok {
+			keys := make([]string, 0, len(g)+len(w))
+			for k := range g {
+				keys = append(keys, k)
+			}
+			for k := range w {
+				if _, ok := g[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				d.child(path+"."+k, g, w, k)
+			}
+			return
+		}
+	case []any:
+		if w, ok := want.([]any); ok {
+			for i := 0; i < len(g) || i < len(w); i++ {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(w):
+					d.add(p, g[i], missing{})
+				case i >= len(g):
+					d.add(p, missing{}, w[i])
+				default:
+					d.value(p, g[i], w[i])
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		d.add(path, got, want)
+	}
+}
+
+// child compares the entries for key in two mappings.
+func (d *differ) child(path string, got, want map[string]any, key string) {
+	g, gok := got[key]
+	w, wok := want[key]
+	switch {
+	case !wok:
+		d.add(path, g, missing{})
+	case !gok:
+		d.add(path, missing{}, w)
+	default:
+		d.value(path, g, w)
+	}
+}
+
+// missing marks a key or element present on only one side.
+type missing struct{}
+
+func format(v any) string {
+	switch v := v.(type) {
+	case missing:
+		return "<missing>"
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case map[string]any:
+		return "{mapping}"
+	case []any:
+		return fmt.Sprintf("[%d items]", len(v))
+	}
+	return fmt.Sprintf("%v (%T)", v, v)
+}
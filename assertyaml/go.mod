@@ -0,0 +1,10 @@
+module github.com/bhuvneshuchiha/assert/assertyaml
+
+go 1.24.2
+
+require (
+	github.com/bhuvneshuchiha/assert v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/bhuvneshuchiha/assert => ../
@@ -0,0 +1,115 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"log/slog"
+	"testing"
+)
+
+// The benchmarks measure the overhead of passing assertions, which is what a
+// program pays in production. Each runs with assertions enabled and disabled.
+//
+//	go test -run '^$' -bench . -benchmem
+
+var sink int
+
+func benchBoth(b *testing.B, fn func(b *testing.B)) {
+	b.Run("enabled", func(b *testing.B) {
+		b.ReportAllocs()
+		fn(b)
+	})
+	b.Run("disabled", func(b *testing.B) {
+		Disable()
+		defer Enable()
+		b.ReportAllocs()
+		fn(b)
+	})
+}
+
+func BenchmarkAssertPass(b *testing.B) {
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Assert(i >= 0, "i must not be negative")
+		}
+	})
+}
+
+func BenchmarkAssertPassData(b *testing.B) {
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Assert(i >= 0, "i must not be negative", "i", i)
+		}
+	})
+}
+
+func BenchmarkAssertPassConstData(b *testing.B) {
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Assert(i >= 0, "i must not be negative", "stage", "bench")
+		}
+	})
+}
+
+func BenchmarkAssertAttrsPass(b *testing.B) {
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			AssertAttrs(i >= 0, "i must not be negative", slog.Int("i", i), slog.String("stage", "bench"))
+		}
+	})
+}
+
+func BenchmarkNilPass(b *testing.B) {
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Nil(nil, "must be nil")
+		}
+	})
+}
+
+func BenchmarkNotNilPass(b *testing.B) {
+	p := &sink
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NotNil(p, "must not be nil")
+		}
+	})
+}
+
+func BenchmarkNotNilPtrPass(b *testing.B) {
+	p := &sink
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NotNilPtr(p, "must not be nil")
+		}
+	})
+}
+
+func BenchmarkNoErrorPass(b *testing.B) {
+	var err error
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NoError(err, "must not fail")
+		}
+	})
+}
+
+func BenchmarkCheckNoErrorPass(b *testing.B) {
+	var err error
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if CheckNoError(err, "must not fail") != nil {
+				b.Fatal("unexpected failure")
+			}
+		}
+	})
+}
+
+func BenchmarkSampledPass(b *testing.B) {
+	cond := func() bool { return true }
+	benchBoth(b, func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Sampled(0.01, cond, "must hold")
+		}
+	})
+}
@@ -0,0 +1,494 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// binaryVersion is the version of the encoding written by MarshalBinary.
+const binaryVersion = 1
+
+// Tags of the values in an encoded Failure's data.
+const (
+	tagNil byte = iota
+	tagBool
+	tagInt
+	tagInt8
+	tagInt16
+	tagInt32
+	tagInt64
+	tagUint
+	tagUint8
+	tagUint16
+	tagUint32
+	tagUint64
+	tagFloat32
+	tagFloat64
+	tagString
+	tagBytes
+	tagTime
+	tagDuration
+	tagError
+	tagText
+	tagGob
+)
+
+// MarshalBinary encodes f in a compact binary form that UnmarshalBinary
+// decodes. Data values of the basic Go kinds, []byte, time.Time and
+// time.Duration keep their types. Values of other types registered with
+// gob.Register, such as structs, maps and named types, are gob encoded and
+// keep their types too, provided the decoding program registers them as
+// well. Otherwise named basic types decode as their underlying kind, errors
+// as errors with the same message, and other values as strings formatted as
+// in text reports.
+func (f Failure) MarshalBinary() ([]byte, error) {
+	return f.appendBinary(nil), nil
+}
+
+func (f Failure) appendBinary(b []byte) []byte {
+	b = append(b, binaryVersion)
+	b = appendString(b, f.Message)
+	b = appendString(b, f.Area)
+	b = binary.AppendVarint(b, int64(f.Severity))
+	b = binary.AppendVarint(b, int64(f.Mode))
+	b = appendString(b, f.File)
+	b = binary.AppendVarint(b, int64(f.Line))
+	b = appendString(b, f.Function)
+	b = appendTime(b, f.Time)
+	b = appendString(b, f.Expression)
+	b = appendPairs(b, f.Data)
+	b = appendString(b, f.Stack)
+	b = appendString(b, f.Fingerprint)
+	b = binary.AppendUvarint(b, f.Suppressed)
+	b = appendString(b, f.ID)
+	b = appendEvents(b, f.Breadcrumbs)
+	b = appendEvents(b, f.Logs)
+	if f.SimSeed != nil {
+		b = binary.AppendUvarint(append(b, 1), *f.SimSeed)
+	} else {
+		b = append(b, 0)
+	}
+	if p := f.Process; p != nil {
+		b = binary.AppendVarint(append(b, 1), int64(p.PID))
+		b = binary.AppendUvarint(b, uint64(len(p.Args)))
+		for _, arg := range p.Args {
+			b = appendString(b, arg)
+		}
+		b = appendString(b, p.Hostname)
+		b = appendString(b, p.GoVersion)
+		b = appendString(b, p.GOOS)
+		b = appendString(b, p.GOARCH)
+	} else {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func appendPairs(b []byte, data []any) []byte {
+	attrs := attrs(data)
+	b = binary.AppendUvarint(b, uint64(len(attrs)))
+	for _, a := range attrs {
+		b = appendString(b, a.Key)
+		b = appendValue(b, a.Value)
+	}
+	return b
+}
+
+func appendEvents(b []byte, events []Event) []byte {
+	b = binary.AppendUvarint(b, uint64(len(events)))
+	for _, e := range events {
+		b = appendTime(b, e.Time)
+		b = appendString(b, e.Level)
+		b = appendString(b, e.Message)
+		b = appendPairs(b, e.Data)
+	}
+	return b
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendTime(b []byte, t time.Time) []byte {
+	tb, err := t.MarshalBinary()
+	if err != nil {
+		// The zone offset is not a whole minute; keep the instant.
+		tb, _ = t.UTC().MarshalBinary()
+	}
+	return appendString(b, string(tb))
+}
+
+func appendValue(b []byte, v any) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, tagNil)
+	case bool:
+		if v {
+			return append(b, tagBool, 1)
+		}
+		return append(b, tagBool, 0)
+	case int:
+		return binary.AppendVarint(append(b, tagInt), int64(v))
+	case int8:
+		return binary.AppendVarint(append(b, tagInt8), int64(v))
+	case int16:
+		return binary.AppendVarint(append(b, tagInt16), int64(v))
+	case int32:
+		return binary.AppendVarint(append(b, tagInt32), int64(v))
+	case int64:
+		return binary.AppendVarint(append(b, tagInt64), v)
+	case uint:
+		return binary.AppendUvarint(append(b, tagUint), uint64(v))
+	case uint8:
+		return binary.AppendUvarint(append(b, tagUint8), uint64(v))
+	case uint16:
+		return binary.AppendUvarint(append(b, tagUint16), uint64(v))
+	case uint32:
+		return binary.AppendUvarint(append(b, tagUint32), uint64(v))
+	case uint64:
+		return binary.AppendUvarint(append(b, tagUint64), v)
+	case float32:
+		return binary.LittleEndian.AppendUint32(append(b, tagFloat32), math.Float32bits(v))
+	case float64:
+		return binary.LittleEndian.AppendUint64(append(b, tagFloat64), math.Float64bits(v))
+	case string:
+		return appendString(append(b, tagString), v)
+	case []byte:
+		return appendString(append(b, tagBytes), string(v))
+	case time.Time:
+		return appendTime(append(b, tagTime), v)
+	case time.Duration:
+		return binary.AppendVarint(append(b, tagDuration), int64(v))
+	case error:
+		return appendString(append(b, tagError), v.Error())
+	}
+	// Registered types are encoded with gob, followed by their text for
+	// programs that cannot decode them.
+	var gb bytes.Buffer
+	if err := gob.NewEncoder(&gb).Encode(&v); err == nil {
+		b = appendString(append(b, tagGob), gb.String())
+		return appendString(b, fmt.Sprintf("%v", v))
+	}
+	if u, ok := underlying(v); ok {
+		return appendValue(b, u)
+	}
+	return appendString(append(b, tagText), fmt.Sprintf("%v", v))
+}
+
+// underlying converts a value of a named basic type, such as a named int, to
+// its underlying type.
+func underlying(v any) (any, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), true
+	case reflect.Int:
+		return int(rv.Int()), true
+	case reflect.Int8:
+		return int8(rv.Int()), true
+	case reflect.Int16:
+		return int16(rv.Int()), true
+	case reflect.Int32:
+		return int32(rv.Int()), true
+	case reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint:
+		return uint(rv.Uint()), true
+	case reflect.Uint8:
+		return uint8(rv.Uint()), true
+	case reflect.Uint16:
+		return uint16(rv.Uint()), true
+	case reflect.Uint32:
+		return uint32(rv.Uint()), true
+	case reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), true
+	case reflect.Float32:
+		return float32(rv.Float()), true
+	case reflect.Float64:
+		return rv.Float(), true
+	case reflect.String:
+		return rv.String(), true
+	}
+	return nil, false
+}
+
+// UnmarshalBinary decodes a Failure encoded by MarshalBinary.
+func (f *Failure) UnmarshalBinary(data []byte) error {
+	d := decoder{b: data}
+	var out Failure
+	switch v := d.byte(); {
+	case d.err != nil:
+		return d.err
+	case v == 1:
+		out = d.failureV1()
+	default:
+		return fmt.Errorf("assert: unsupported binary failure version %d", v)
+	}
+	if len(d.b) > 0 {
+		d.fail()
+	}
+	if d.err != nil {
+		return d.err
+	}
+	*f = out
+	return nil
+}
+
+// failureV1 decodes the fields of a Failure encoded with version 1.
+func (d *decoder) failureV1() Failure {
+	var f Failure
+	f.Message = d.string()
+	f.Area = d.string()
+	f.Severity = Severity(d.varint())
+	f.Mode = Mode(d.varint())
+	f.File = d.string()
+	f.Line = int(d.varint())
+	f.Function = d.string()
+	f.Time = d.time()
+	f.Expression = d.string()
+	f.Data = d.pairs()
+	f.Stack = d.string()
+	f.Fingerprint = d.string()
+	f.Suppressed = d.uvarint()
+	f.ID = d.string()
+	f.Breadcrumbs = d.events()
+	f.Logs = d.events()
+	if d.byte() == 1 {
+		seed := d.uvarint()
+		f.SimSeed = &seed
+	}
+	if d.byte() == 1 {
+		p := &Process{PID: int(d.varint())}
+		if n := d.uvarint(); n <= uint64(len(d.b)) {
+			for range n {
+				p.Args = append(p.Args, d.string())
+			}
+		} else {
+			d.fail()
+		}
+		p.Hostname = d.string()
+		p.GoVersion = d.string()
+		p.GOOS = d.string()
+		p.GOARCH = d.string()
+		f.Process = p
+	}
+	return f
+}
+
+var errBinaryFailure = errors.New("assert: malformed binary failure")
+
+// decoder reads the encoding written by appendBinary, remembering the first
+// error so that callers check once at the end.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = errBinaryFailure
+	}
+	d.b = nil
+}
+
+func (d *decoder) byte() byte {
+	if len(d.b) == 0 {
+		d.fail()
+		return 0
+	}
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if n < 0 || n > len(d.b) {
+		d.fail()
+		return nil
+	}
+	v := d.b[:n:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.fail()
+		return ""
+	}
+	return string(d.bytes(int(n)))
+}
+
+func (d *decoder) time() time.Time {
+	var t time.Time
+	if s := d.string(); d.err == nil {
+		if err := t.UnmarshalBinary([]byte(s)); err != nil {
+			d.fail()
+		}
+	}
+	return t
+}
+
+func (d *decoder) pairs() []any {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.fail()
+		return nil
+	}
+	var data []any
+	for range n {
+		key := d.string()
+		data = append(data, key, d.value())
+	}
+	return data
+}
+
+func (d *decoder) events() []Event {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.fail()
+		return nil
+	}
+	var events []Event
+	for range n {
+		e := Event{Time: d.time(), Level: d.string(), Message: d.string()}
+		e.Data = d.pairs()
+		events = append(events, e)
+	}
+	return events
+}
+
+func (d *decoder) value() any {
+	switch tag := d.byte(); tag {
+	case tagNil:
+		return nil
+	case tagBool:
+		return d.byte() != 0
+	case tagInt:
+		return int(d.varint())
+	case tagInt8:
+		return int8(d.varint())
+	case tagInt16:
+		return int16(d.varint())
+	case tagInt32:
+		return int32(d.varint())
+	case tagInt64:
+		return d.varint()
+	case tagUint:
+		return uint(d.uvarint())
+	case tagUint8:
+		return uint8(d.uvarint())
+	case tagUint16:
+		return uint16(d.uvarint())
+	case tagUint32:
+		return uint32(d.uvarint())
+	case tagUint64:
+		return d.uvarint()
+	case tagFloat32:
+		if b := d.bytes(4); b != nil {
+			return math.Float32frombits(binary.LittleEndian.Uint32(b))
+		}
+	case tagFloat64:
+		if b := d.bytes(8); b != nil {
+			return math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+	case tagString, tagText:
+		return d.string()
+	case tagBytes:
+		return []byte(d.string())
+	case tagTime:
+		return d.time()
+	case tagDuration:
+		return time.Duration(d.varint())
+	case tagError:
+		return errors.New(d.string())
+	case tagGob:
+		enc, text := d.string(), d.string()
+		var v any
+		if err := gob.NewDecoder(strings.NewReader(enc)).Decode(&v); err != nil {
+			return text
+		}
+		return v
+	default:
+		d.fail()
+	}
+	return nil
+}
+
+// maxBinaryRecord is the size of the largest record FailureDecoder accepts.
+const maxBinaryRecord = 64 << 20
+
+// FailureDecoder reads the failures written with FormatBinary, each a
+// length prefixed record.
+type FailureDecoder struct {
+	r *bufio.Reader
+}
+
+// NewFailureDecoder returns a decoder reading failures from r.
+func NewFailureDecoder(r io.Reader) *FailureDecoder {
+	return &FailureDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next failure. It returns io.EOF when the stream ends
+// between records, and an error for records longer than 64 MiB.
+func (d *FailureDecoder) Decode() (Failure, error) {
+	var f Failure
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return f, err
+	}
+	if n > maxBinaryRecord {
+		return f, fmt.Errorf("assert: binary failure record of %d bytes exceeds the limit of %d", n, maxBinaryRecord)
+	}
+	// The length comes from the stream: read what is there rather than
+	// allocating all of it up front.
+	b, err := io.ReadAll(io.LimitReader(d.r, int64(n)))
+	if err != nil {
+		return f, err
+	}
+	if uint64(len(b)) < n {
+		return f, io.ErrUnexpectedEOF
+	}
+	err = f.UnmarshalBinary(b)
+	return f, err
+}
+
+// renderBinary writes r's Failure as a length prefixed record.
+func (r *report) renderBinary(b *bytes.Buffer) {
+	rec := r.failure().appendBinary(nil)
+	b.Write(binary.AppendUvarint(nil, uint64(len(rec))))
+	b.Write(rec)
+}
@@ -0,0 +1,157 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type point struct{ X, Y int }
+
+func init() {
+	gob.Register(point{})
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		in   any
+		want any
+	}{
+		{"nil", nil, nil},
+		{"bool", true, true},
+		{"int", -7, -7},
+		{"int8", int8(-8), int8(-8)},
+		{"int16", int16(-16), int16(-16)},
+		{"int32", int32(-32), int32(-32)},
+		{"int64", int64(math.MinInt64), int64(math.MinInt64)},
+		{"uint", uint(7), uint(7)},
+		{"uint8", uint8(8), uint8(8)},
+		{"uint16", uint16(16), uint16(16)},
+		{"uint32", uint32(32), uint32(32)},
+		{"uint64", uint64(math.MaxUint64), uint64(math.MaxUint64)},
+		{"float32", float32(1.5), float32(1.5)},
+		{"float64", 2.25, 2.25},
+		{"inf", math.Inf(1), math.Inf(1)},
+		{"string", "text", "text"},
+		{"bytes", []byte{0, 1, 2}, []byte{0, 1, 2}},
+		{"time", at, at},
+		{"duration", 3 * time.Second, 3 * time.Second},
+		{"error", errors.New("boom"), errors.New("boom")},
+		{"named", celsius(21.5), 21.5},
+		{"unregistered struct", struct{ A int }{1}, "{1}"},
+		{"registered struct", point{1, 2}, point{1, 2}},
+		{"slice", []string{"a", "b"}, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testReport("v", tt.in).failure()
+			b, err := f.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got Failure
+			if err := got.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if v := got.Data[1]; !reflect.DeepEqual(v, tt.want) {
+				t.Errorf("value = %#v, want %#v", v, tt.want)
+			}
+			got.Data, f.Data = nil, nil
+			if !got.Time.Equal(f.Time) {
+				t.Errorf("time = %v, want %v", got.Time, f.Time)
+			}
+			got.Time, f.Time = time.Time{}, time.Time{}
+			for i := range got.Breadcrumbs {
+				got.Breadcrumbs[i].Time, f.Breadcrumbs[i].Time = time.Time{}, time.Time{}
+			}
+			if !reflect.DeepEqual(got, f) {
+				t.Errorf("got %+v, want %+v", got, f)
+			}
+		})
+	}
+}
+
+func TestBinaryNaN(t *testing.T) {
+	b, _ := testReport("v", math.NaN()).failure().MarshalBinary()
+	var got Failure
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := got.Data[1].(float64); !ok || !math.IsNaN(v) {
+		t.Errorf("value = %#v, want NaN", got.Data[1])
+	}
+}
+
+func TestBinaryMalformed(t *testing.T) {
+	b, _ := testReport("v", 1).failure().MarshalBinary()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"version", append([]byte{binaryVersion + 1}, b[1:]...)},
+		{"truncated", b[:len(b)/2]},
+		{"trailing bytes", append(b[:len(b):len(b)], 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f Failure
+			if err := f.UnmarshalBinary(tt.data); err == nil {
+				t.Error("UnmarshalBinary succeeded")
+			}
+		})
+	}
+}
+
+func TestFailureDecoder(t *testing.T) {
+	var b bytes.Buffer
+	for _, msg := range []string{"first", "second"} {
+		r := testReport("k", 1)
+		r.msg = msg
+		r.renderBinary(&b)
+	}
+	dec := NewFailureDecoder(&b)
+	for _, want := range []string{"first", "second"} {
+		f, err := dec.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Message != want {
+			t.Errorf("message = %q, want %q", f.Message, want)
+		}
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Errorf("Decode at end = %v, want io.EOF", err)
+	}
+}
+
+func TestFailureDecoderLength(t *testing.T) {
+	tests := []struct {
+		name string
+		n    uint64
+		want error // nil for any error
+	}{
+		{"over the limit", 1 << 40, nil},
+		{"missing record", maxBinaryRecord, io.ErrUnexpectedEOF},
+		{"short record", 8, io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := append(binary.AppendUvarint(nil, tt.n), binaryVersion, 1, 'x')
+			_, err := NewFailureDecoder(bytes.NewReader(in)).Decode()
+			if err == nil || tt.want != nil && err != tt.want {
+				t.Errorf("Decode = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// crumbsPerShard is how many breadcrumbs each shard keeps.
+const crumbsPerShard = 32
+
+// maxReportedCrumbs is how many breadcrumbs a report includes.
+const maxReportedCrumbs = 32
+
+type breadcrumb struct {
+	time time.Time
+	msg  string
+	data []any
+}
+
+func (b breadcrumb) String() string {
+	var s strings.Builder
+	s.WriteString(b.time.Format("15:04:05.000000"))
+	s.WriteByte(' ')
+	s.WriteString(b.msg)
+	for i := 0; i+1 < len(b.data); i += 2 {
+		fmt.Fprintf(&s, " %v=%v", b.data[i], b.data[i+1])
+	}
+	return s.String()
+}
+
+type crumbShard struct {
+	mu   sync.Mutex
+	ring [crumbsPerShard]breadcrumb
+	next int
+	_    [cacheLine]byte
+}
+
+var crumbShards = make([]crumbShard, shardCount)
+
+// Breadcrumb records a short event. The most recent breadcrumbs are included
+// in every report, showing what the program was doing before an invariant
+// broke. Recording is cheap and safe from any number of goroutines: events
+// are spread over as many buffers of 32 as there are CPUs, and a report
+// merges them by time and includes the latest 32.
+func Breadcrumb(msg string, data ...any) {
+	b := breadcrumb{time: now(), msg: msg, data: slices.Clone(data)}
+
+	s := &crumbShards[shardIndex()]
+	s.mu.Lock()
+	s.ring[s.next] = b
+	s.next = (s.next + 1) % crumbsPerShard
+	s.mu.Unlock()
+}
+
+// recentBreadcrumbs returns up to n of the latest breadcrumbs, oldest first.
+func recentBreadcrumbs(n int) []breadcrumb {
+	var all []breadcrumb
+	for i := range crumbShards {
+		s := &crumbShards[i]
+		s.mu.Lock()
+		for _, b := range s.ring {
+			if !b.time.IsZero() {
+				all = append(all, b)
+			}
+		}
+		s.mu.Unlock()
+	}
+	slices.SortFunc(all, func(a, b breadcrumb) int { return a.time.Compare(b.time) })
+	if len(all) > n {
+		all = all[len(all)-n:]
+	}
+	return all
+}
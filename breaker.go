@@ -0,0 +1,84 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// breaker detects failure storms in ModeWarn. While a storm lasts, failures
+// are counted instead of reported and a summary is written periodically, so
+// diagnostics cannot add to the latency of a struggling service.
+type breaker struct {
+	mu           sync.Mutex
+	limit        int
+	summaryEvery time.Duration
+	windowStart  time.Time
+	windowCount  int
+	tripped      bool
+	counted      uint64
+	lastSummary  time.Time
+}
+
+var stormBreaker breaker
+
+// SetCircuitBreaker makes warn-mode reporting downgrade to counting only
+// once more than perSecond failures happen within a second. While tripped, a
+// summary of the counted failures is written every summaryEvery, to the
+// output routed for the area of the failure that prompts it; reporting
+// resumes after a second with at most perSecond failures. A zero perSecond
+// disables the breaker, which is the default.
+func SetCircuitBreaker(perSecond int, summaryEvery time.Duration) {
+	stormBreaker.mu.Lock()
+	defer stormBreaker.mu.Unlock()
+
+	stormBreaker.limit = perSecond
+	stormBreaker.summaryEvery = summaryEvery
+	stormBreaker.windowStart = time.Time{}
+	stormBreaker.windowCount = 0
+	stormBreaker.tripped = false
+	stormBreaker.counted = 0
+}
+
+// record counts a failure and reports whether it must not be reported. It
+// returns a summary line to write when the breaker changes state or a
+// periodic summary is due.
+func (b *breaker) record(now time.Time) (suppress bool, summary string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit <= 0 {
+		return false, ""
+	}
+
+	if now.Sub(b.windowStart) >= time.Second {
+		calm := b.windowCount <= b.limit
+		b.windowStart = now
+		b.windowCount = 0
+		if b.tripped && calm {
+			b.tripped = false
+			summary = fmt.Sprintf("assert: failure storm over, %d failures were counted but not reported\n", b.counted)
+			b.counted = 0
+		}
+	}
+	b.windowCount++
+
+	if !b.tripped && b.windowCount > b.limit {
+		b.tripped = true
+		b.lastSummary = now
+		summary = fmt.Sprintf("assert: more than %d failures per second, counting failures without reporting them\n", b.limit)
+	}
+	if !b.tripped {
+		return false, summary
+	}
+
+	b.counted++
+	if b.summaryEvery > 0 && now.Sub(b.lastSummary) >= b.summaryEvery {
+		summary = fmt.Sprintf("assert: failure storm, %d failures counted in the last %v\n", b.counted, now.Sub(b.lastSummary).Round(time.Millisecond))
+		b.counted = 0
+		b.lastSummary = now
+	}
+	return true, summary
+}
@@ -0,0 +1,91 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"runtime"
+	"time"
+)
+
+// Within starts timing the enclosing scope and returns a function that
+// asserts the scope finished within budget. Call the result when the scope
+// ends, typically with defer:
+//
+//	defer assert.Within(5*time.Millisecond, "compact step too slow", "level", lvl)()
+//
+// The report includes the budget and the time actually taken. Pass
+// SeverityKey with SeverityWarn to only report overruns. The time is read
+// from the simulation clock while one is installed.
+func Within(budget time.Duration, msg string, data ...any) func() {
+	if !Enabled() {
+		return func() {}
+	}
+	start := now()
+	return func() {
+		if elapsed := now().Sub(start); elapsed > budget {
+			runAssert(msg, append(data, "budget", budget.String(), "elapsed", elapsed.String())...)
+		} else if tracking.Load() {
+			passed(msg, data)
+		}
+	}
+}
+
+// AllocsUnder runs fn and asserts that it made at most n heap allocations,
+// so a hot path can enforce zero allocations as an invariant:
+//
+//	assert.AllocsUnder(0, func() { enc.Encode(rec) }, "encoder allocated")
+//
+// Allocations are counted with runtime.ReadMemStats, as
+// testing.AllocsPerRun does, which briefly stops the world and counts
+// allocations by every goroutine. Use it in debug builds and on paths where
+// little else runs concurrently. When assertions are disabled fn runs
+// unmeasured.
+func AllocsUnder(n uint64, fn func(), msg string, data ...any) {
+	if !Enabled() {
+		fn()
+		return
+	}
+	start := mallocs()
+	fn()
+	allocsChecked(n, mallocs()-start, msg, data)
+}
+
+// AllocsWithin is the scope based form of AllocsUnder. It returns a function
+// that asserts at most n heap allocations were made since AllocsWithin was
+// called:
+//
+//	defer assert.AllocsWithin(0, "lookup allocated")()
+func AllocsWithin(n uint64, msg string, data ...any) func() {
+	if !Enabled() {
+		return func() {}
+	}
+	// Allocate the returned function before counting starts.
+	s := &allocScope{n: n, msg: msg, data: data}
+	done := s.done
+	s.start = mallocs()
+	return done
+}
+
+type allocScope struct {
+	n, start uint64
+	msg      string
+	data     []any
+}
+
+func (s *allocScope) done() {
+	allocsChecked(s.n, mallocs()-s.start, s.msg, s.data)
+}
+
+func allocsChecked(n, allocs uint64, msg string, data []any) {
+	if allocs > n {
+		runAssert(msg, append(data, "allocs.budget", n, "allocs", allocs)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+func mallocs() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.Mallocs
+}
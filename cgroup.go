@@ -0,0 +1,20 @@
+//go:build !tinygo
+
+package assert
+
+// IncludeCgroup controls whether reports include the memory and CPU limits
+// of the process's cgroup and its usage against them, so failures that
+// correlate with throttling or memory pressure in a container can be
+// diagnosed from the report alone:
+//
+//	cgroup.memory.limit=536870912
+//	cgroup.memory.usage=529481728
+//	cgroup.memory.oom_kills=2
+//	cgroup.cpu.limit=1.5
+//	cgroup.cpu.throttled=1432 periods, 1m12.5s
+//
+// Limits are read from cgroup v1 or v2 on Linux and reported as "unlimited"
+// when not set; elsewhere the section is left out. It is off by default.
+func IncludeCgroup(on bool) {
+	updateConfig(func(c *config) { c.cgroup = on })
+}
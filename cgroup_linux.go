@@ -0,0 +1,168 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupPairs returns the memory and CPU limits and usage of the process's
+// cgroup.
+func cgroupPairs() []any {
+	paths := cgroupPaths()
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		if p, ok := paths[""]; ok {
+			return cgroup2Pairs(cgroupDir(cgroupRoot, p))
+		}
+	}
+	var pairs []any
+	if p, ok := paths["memory"]; ok {
+		pairs = append(pairs, cgroup1MemoryPairs(cgroupDir(filepath.Join(cgroupRoot, "memory"), p))...)
+	}
+	if p, ok := paths["cpu"]; ok {
+		pairs = append(pairs, cgroup1CPUPairs(cgroupDir(filepath.Join(cgroupRoot, "cpu"), p))...)
+	}
+	return pairs
+}
+
+// cgroupPaths returns the cgroup of the process for each v1 controller, and
+// for v2 under the empty name.
+func cgroupPaths() map[string]string {
+	paths := map[string]string{}
+	f, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return paths
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		// hierarchy-ID:controller-list:path
+		parts := strings.SplitN(sc.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		for _, name := range strings.Split(parts[1], ",") {
+			paths[name] = parts[2]
+		}
+	}
+	return paths
+}
+
+// cgroupDir returns the directory of the cgroup at path under root. Inside a
+// cgroup namespace the process's cgroup is mounted at the root itself.
+func cgroupDir(root, path string) string {
+	dir := filepath.Join(root, path)
+	if _, err := os.Stat(dir); err != nil {
+		return root
+	}
+	return dir
+}
+
+func cgroup2Pairs(dir string) []any {
+	var pairs []any
+	if v, ok := readCgroupFile(dir, "memory.max"); ok {
+		pairs = append(pairs, "cgroup.memory.limit", v)
+	}
+	if v, ok := readCgroupFile(dir, "memory.current"); ok {
+		pairs = append(pairs, "cgroup.memory.usage", v)
+	}
+	if v, ok := cgroupStat(dir, "memory.events")["oom_kill"]; ok {
+		pairs = append(pairs, "cgroup.memory.oom_kills", v)
+	}
+	if v, ok := readCgroupFile(dir, "cpu.max"); ok {
+		quota, period, _ := strings.Cut(v, " ")
+		pairs = append(pairs, "cgroup.cpu.limit", cpuLimit(quota, period))
+	}
+	stat := cgroupStat(dir, "cpu.stat")
+	if n, ok := stat["nr_throttled"]; ok {
+		usec, _ := strconv.ParseInt(stat["throttled_usec"], 10, 64)
+		pairs = append(pairs, "cgroup.cpu.throttled", fmt.Sprintf("%s periods, %v", n, time.Duration(usec)*time.Microsecond))
+	}
+	return pairs
+}
+
+// unlimitedV1 is the least memory limit cgroup v1 reports when none is set,
+// a page-aligned maximum that differs between kernels and architectures.
+const unlimitedV1 = 1 << 62
+
+func cgroup1MemoryPairs(dir string) []any {
+	var pairs []any
+	if v, ok := readCgroupFile(dir, "memory.limit_in_bytes"); ok {
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil && n >= unlimitedV1 {
+			v = "unlimited"
+		}
+		pairs = append(pairs, "cgroup.memory.limit", v)
+	}
+	if v, ok := readCgroupFile(dir, "memory.usage_in_bytes"); ok {
+		pairs = append(pairs, "cgroup.memory.usage", v)
+	}
+	if v, ok := cgroupStat(dir, "memory.oom_control")["oom_kill"]; ok {
+		pairs = append(pairs, "cgroup.memory.oom_kills", v)
+	}
+	return pairs
+}
+
+func cgroup1CPUPairs(dir string) []any {
+	var pairs []any
+	quota, ok := readCgroupFile(dir, "cpu.cfs_quota_us")
+	period, ok2 := readCgroupFile(dir, "cpu.cfs_period_us")
+	if ok && ok2 {
+		if quota == "-1" {
+			quota = "max"
+		}
+		pairs = append(pairs, "cgroup.cpu.limit", cpuLimit(quota, period))
+	}
+	stat := cgroupStat(dir, "cpu.stat")
+	if n, ok := stat["nr_throttled"]; ok {
+		ns, _ := strconv.ParseInt(stat["throttled_time"], 10, 64)
+		pairs = append(pairs, "cgroup.cpu.throttled", fmt.Sprintf("%s periods, %v", n, time.Duration(ns)))
+	}
+	return pairs
+}
+
+// cpuLimit formats a CFS quota and period as a number of CPUs.
+func cpuLimit(quota, period string) string {
+	q, err := strconv.ParseFloat(quota, 64)
+	p, err2 := strconv.ParseFloat(period, 64)
+	if err != nil || err2 != nil || p <= 0 {
+		return "unlimited"
+	}
+	return strconv.FormatFloat(q/p, 'f', -1, 64)
+}
+
+// readCgroupFile returns the trimmed content of a single-value cgroup file,
+// with "max" reported as "unlimited".
+func readCgroupFile(dir, name string) (string, bool) {
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "", false
+	}
+	v := strings.TrimSpace(string(b))
+	if v == "max" {
+		v = "unlimited"
+	}
+	return v, true
+}
+
+// cgroupStat reads a cgroup file of "key value" lines.
+func cgroupStat(dir, name string) map[string]string {
+	stat := map[string]string{}
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return stat
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if k, v, ok := strings.Cut(line, " "); ok {
+			stat[k] = strings.TrimSpace(v)
+		}
+	}
+	return stat
+}
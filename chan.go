@@ -0,0 +1,78 @@
+//go:build !tinygo
+
+package assert
+
+import "strconv"
+
+// Channel assertions take a receive-only channel, so they accept any channel
+// that can be received from.
+
+// ChanClosed asserts that c is closed and drained. Go cannot tell whether a
+// channel is closed without receiving from it, so ChanClosed does so when
+// the buffer is empty, where a receive that succeeds without blocking proves
+// the channel closed. The probe does not block, but on an open channel it
+// takes the value of a concurrent sender, such as one blocked on an
+// unbuffered channel. The assertion then fails and returns that value with
+// took set, so that a caller in ModeWarn can hand it on instead of losing it.
+func ChanClosed[T any](c <-chan T, msg string, data ...any) (v T, took bool) {
+	v, took, closed := chanProbe(c)
+	if !closed || tracking.Load() {
+		if took {
+			data = append(data, "chan.received", v)
+		}
+		chanChecked(!closed, "closed", len(c), cap(c), msg, data)
+	}
+	return v, took
+}
+
+// ChanNotClosed asserts that c is not closed, where that can be detected:
+// it uses the same best-effort probe as ChanClosed, so it only detects
+// closing when the buffer is empty, and a channel with buffered values
+// passes. A passing ChanNotClosed may have taken a sender's value, which it
+// returns with took set; callers of ChanNotClosed on channels with
+// concurrent senders must handle it.
+func ChanNotClosed[T any](c <-chan T, msg string, data ...any) (v T, took bool) {
+	v, took, closed := chanProbe(c)
+	if closed || tracking.Load() {
+		chanChecked(closed, "open", len(c), cap(c), msg, data)
+	}
+	return v, took
+}
+
+// ChanEmpty asserts that c has no buffered values, such as a work queue that
+// must have been drained.
+func ChanEmpty[T any](c <-chan T, msg string, data ...any) {
+	if n := len(c); n != 0 || tracking.Load() {
+		chanChecked(n != 0, "empty", n, cap(c), msg, data)
+	}
+}
+
+// ChanLen asserts that c has exactly n buffered values.
+func ChanLen[T any](c <-chan T, n int, msg string, data ...any) {
+	if l := len(c); l != n || tracking.Load() {
+		chanChecked(l != n, "len "+strconv.Itoa(n), l, cap(c), msg, data)
+	}
+}
+
+// chanProbe receives from c without blocking if it is not nil and has no
+// buffered values. It returns the value received, if any, and whether the
+// receive found c closed.
+func chanProbe[T any](c <-chan T) (v T, took, closed bool) {
+	if c == nil || len(c) > 0 {
+		return v, false, false
+	}
+	select {
+	case v, ok := <-c:
+		return v, ok, !ok
+	default:
+		return v, false, false
+	}
+}
+
+func chanChecked(failed bool, want string, n, size int, msg string, data []any) {
+	if !failed {
+		passed(msg, data)
+		return
+	}
+	runAssert(msg, append(data, "chan.want", want, "chan.len", n, "chan.cap", size)...)
+}
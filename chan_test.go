@@ -0,0 +1,62 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestChanClosed(t *testing.T) {
+	buffered := make(chan int, 1)
+	buffered <- 1
+	closedBuffered := make(chan int, 1)
+	closedBuffered <- 1
+	close(closedBuffered)
+	closed := make(chan int)
+	close(closed)
+
+	tests := []struct {
+		name      string
+		c         chan int
+		closedErr bool // ChanClosed fails
+		openErr   bool // ChanNotClosed fails
+	}{
+		{"nil", nil, true, false},
+		{"open", make(chan int), true, false},
+		{"buffered", buffered, true, false},
+		{"closed", closed, false, true},
+		// Values left in the buffer keep the probe from running.
+		{"closed with values", closedBuffered, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fails(t, func() { ChanClosed(tt.c, "not closed") }); got != tt.closedErr {
+				t.Errorf("ChanClosed failed = %v, want %v", got, tt.closedErr)
+			}
+			if got := fails(t, func() { ChanNotClosed(tt.c, "closed") }); got != tt.openErr {
+				t.Errorf("ChanNotClosed failed = %v, want %v", got, tt.openErr)
+			}
+		})
+	}
+}
+
+func TestChanProbeReturnsValue(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	SetMode(ModeWarn)
+	ToWriter(io.Discard)
+
+	c := make(chan int)
+	go func() { c <- 7 }()
+	for range 100 {
+		if v, took := ChanClosed(c, "not closed"); took {
+			if v != 7 {
+				t.Fatalf("ChanClosed returned %d, want 7", v)
+			}
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("ChanClosed never took the blocked sender's value")
+}
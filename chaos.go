@@ -0,0 +1,66 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
+
+type chaosState struct {
+	mu   sync.Mutex
+	rng  *rand.Rand
+	rate float64
+	seed uint64
+}
+
+var chaos atomic.Pointer[chaosState]
+
+// EnableChaos makes every ChaosPoint fail with probability rate (0 to 1), so
+// flush handlers, outputs and restart orchestration can be exercised
+// routinely like any other failure path. The points that fail are decided by
+// a generator seeded with seed; a zero seed picks a random one. The seed is
+// included in every chaos failure so a run can be reproduced. Chaos is off
+// by default.
+func EnableChaos(rate float64, seed uint64) {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	chaos.Store(&chaosState{
+		rng:  rand.New(rand.NewPCG(seed, seed)),
+		rate: rate,
+		seed: seed,
+	})
+}
+
+// DisableChaos turns chaos failures off.
+func DisableChaos() {
+	chaos.Store(nil)
+}
+
+// ChaosPoint marks a place where a failure may be forced while chaos is
+// enabled with EnableChaos. The failure is reported like any other, with the
+// point's name and the chaos seed, and then handled according to the current
+// mode. Otherwise ChaosPoint does nothing.
+//
+//	assert.ChaosPoint("orders.commit", "order", o.ID)
+func ChaosPoint(name string, data ...any) {
+	if chaos.Load() != nil {
+		chaosPoint(name, data)
+	}
+}
+
+func chaosPoint(name string, data []any) {
+	s := chaos.Load()
+	if s == nil || !Enabled() {
+		return
+	}
+	s.mu.Lock()
+	fail := s.rng.Float64() < s.rate
+	s.mu.Unlock()
+
+	if fail {
+		runAssert("chaos failure", append(data, "chaos", name, "seed", s.seed)...)
+	}
+}
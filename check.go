@@ -0,0 +1,68 @@
+//go:build !tinygo
+
+package assert
+
+// Check is the non-terminating counterpart of Assert: it returns an
+// *AssertionError describing the failure instead of reporting it, for code
+// such as libraries that must not crash their host process.
+func Check(truth bool, msg string, data ...any) error {
+	if truth || !Enabled() {
+		return checkPassed(msg, data)
+	}
+	return checkFailure(msg, data)
+}
+
+// CheckNil is the non-terminating counterpart of Nil.
+func CheckNil(item any, msg string, data ...any) error {
+	if item == nil || !Enabled() {
+		return checkPassed(msg, data)
+	}
+	return checkFailure(msg, data)
+}
+
+// CheckNotNil is the non-terminating counterpart of NotNil.
+func CheckNotNil(item any, msg string, data ...any) error {
+	if !Enabled() || !isNil(item) {
+		return checkPassed(msg, data)
+	}
+	return checkFailure(msg, data)
+}
+
+// CheckNoError is the non-terminating counterpart of NoError.
+func CheckNoError(err error, msg string, data ...any) error {
+	if err == nil || !Enabled() {
+		return checkPassed(msg, data)
+	}
+	data = append(data, "error", err)
+	return checkFailure(msg, data)
+}
+
+// CheckNever is the non-terminating counterpart of Never.
+func CheckNever(msg string, data ...any) error {
+	if !Enabled() {
+		return nil
+	}
+	return checkFailure(msg, data)
+}
+
+// checkPassed records the site of a passing check when tracking is on.
+func checkPassed(msg string, data []any) error {
+	if tracking.Load() {
+		passed(msg, data)
+	}
+	return nil
+}
+
+func checkFailure(msg string, data []any) error {
+	r := newReport(msg, data)
+	r.mode = ModeWarn // the caller decides what happens next
+	if tracking.Load() {
+		trackFailure(r)
+	}
+	if !AreaEnabled(r.area) {
+		return nil
+	}
+	r.setSite(failureFrame())
+	r.collect(loadConfig())
+	return &AssertionError{r: r}
+}
@@ -0,0 +1,94 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"errors"
+	"sync"
+)
+
+// Checker accumulates the failures of several checks so they can be returned
+// as one error. Every failure is an *AssertionError, so callers can still
+// inspect them individually with errors.As. The zero value is ready to use; a
+// Checker is not safe for concurrent use.
+//
+//	var c assert.Checker
+//	c.Check(o.Total >= 0, "negative total", "order", o.ID)
+//	c.NoError(o.Validate(), "invalid order", "order", o.ID)
+//	return c.Err()
+type Checker struct {
+	errs []error
+}
+
+// Check records a failure if truth is false.
+func (c *Checker) Check(truth bool, msg string, data ...any) {
+	c.add(Check(truth, msg, data...))
+}
+
+// Nil records a failure if item is not nil.
+func (c *Checker) Nil(item any, msg string, data ...any) {
+	c.add(CheckNil(item, msg, data...))
+}
+
+// NotNil records a failure if item is nil or a nil pointer.
+func (c *Checker) NotNil(item any, msg string, data ...any) {
+	c.add(CheckNotNil(item, msg, data...))
+}
+
+// NoError records a failure if err is not nil.
+func (c *Checker) NoError(err error, msg string, data ...any) {
+	c.add(CheckNoError(err, msg, data...))
+}
+
+// Never records a failure unconditionally.
+func (c *Checker) Never(msg string, data ...any) {
+	c.add(CheckNever(msg, data...))
+}
+
+func (c *Checker) add(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// Failed reports whether any check failed.
+func (c *Checker) Failed() bool {
+	return len(c.errs) > 0
+}
+
+// Err returns the recorded failures joined with errors.Join, or nil if every
+// check passed.
+func (c *Checker) Err() error {
+	return errors.Join(c.errs...)
+}
+
+// maxWarnings bounds the failures kept for TakeWarnings; older ones are
+// dropped first.
+const maxWarnings = 256
+
+var warningsMu sync.Mutex
+var warnings []error
+
+// recordWarning keeps a failure reported in ModeWarn for TakeWarnings.
+func recordWarning(r *report) {
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+
+	if len(warnings) == maxWarnings {
+		warnings = append(warnings[:0], warnings[1:]...)
+	}
+	warnings = append(warnings, &AssertionError{r: r, written: true})
+}
+
+// TakeWarnings returns the failures reported in ModeWarn since the last call,
+// at most the most recent 256, joined with errors.Join, or nil if there were
+// none. Failures suppressed by ReportOnce, SetRateLimit or SetCircuitBreaker
+// are not included.
+func TakeWarnings() error {
+	warningsMu.Lock()
+	defer warningsMu.Unlock()
+
+	err := errors.Join(warnings...)
+	warnings = nil
+	return err
+}
@@ -0,0 +1,92 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TrackedCloser wraps an io.Closer registered with TrackCloser. Closing it
+// closes the underlying resource and stops tracking it.
+type TrackedCloser struct {
+	io.Closer
+	name    string
+	created time.Time
+	pcs     []uintptr
+	once    sync.Once
+}
+
+var closersMu sync.Mutex
+var openClosers = map[*TrackedCloser]struct{}{}
+
+// TrackCloser records c as an open resource named name, along with the stack
+// that opened it, until the returned handle is closed. AllClosed reports
+// every resource still open:
+//
+//	f, err := os.Create(path)
+//	...
+//	seg := assert.TrackCloser(f, "wal segment")
+//	defer seg.Close()
+func TrackCloser(c io.Closer, name string) *TrackedCloser {
+	t := &TrackedCloser{Closer: c, name: name, created: now()}
+	var pcs [32]uintptr
+	t.pcs = append([]uintptr(nil), pcs[:runtime.Callers(2, pcs[:])]...)
+	closersMu.Lock()
+	openClosers[t] = struct{}{}
+	closersMu.Unlock()
+	return t
+}
+
+// Close closes the underlying resource. Only the first call untracks it, but
+// every call is passed on, so double closes still surface their errors.
+func (t *TrackedCloser) Close() error {
+	t.once.Do(func() {
+		closersMu.Lock()
+		delete(openClosers, t)
+		closersMu.Unlock()
+	})
+	return t.Closer.Close()
+}
+
+// AllClosed asserts that every resource registered with TrackCloser has been
+// closed. Call it at shutdown, or periodically for resources that should be
+// short-lived. The report lists each open resource, how long it has been
+// open and the stack that opened it.
+func AllClosed(msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	closersMu.Lock()
+	open := make([]*TrackedCloser, 0, len(openClosers))
+	for t := range openClosers {
+		open = append(open, t)
+	}
+	closersMu.Unlock()
+
+	if len(open) == 0 {
+		if tracking.Load() {
+			passed(msg, data)
+		}
+		return
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].created.Before(open[j].created) })
+	descs := make([]string, len(open))
+	for i, t := range open {
+		descs[i] = t.describe()
+	}
+	runAssert(msg, append(data,
+		"closers.open", len(open),
+		"closers", strings.Join(descs, "\n\n"),
+	)...)
+}
+
+func (t *TrackedCloser) describe() string {
+	return fmt.Sprintf("%s, open for %s, opened at:\n%s",
+		t.name, now().Sub(t.created).Round(time.Millisecond), formatStack(t.pcs))
+}
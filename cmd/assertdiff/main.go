@@ -0,0 +1,175 @@
+// Command assertdiff compares two serialized failure reports, such as the
+// same invariant tripping on two nodes, and prints what differs between
+// them: metadata, assert data and other data values, and stack frames. It
+// reads FormatJSON lines, FormatBinary streams and post-mortem files, gzip
+// compressed or not:
+//
+//	assertdiff node1/assert-20240102T150405.000-4242.json node2/assert-20240102T150406.120-977.json
+//	assertdiff -match wal-order node1/asserts.jsonl node2/asserts.jsonl
+//
+// Like diff, it exits with status 1 when the reports differ and 2 on error.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/bhuvneshuchiha/assert"
+	"github.com/bhuvneshuchiha/assert/internal/term"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("assertdiff: ")
+	color := flag.String("color", "auto", `colour output: "auto", "always" or "never"`)
+	match := flag.String("match", "", "compare the first failure in each file whose message, ID or fingerprint matches this regexp")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: assertdiff [flags] a b")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	on, err := term.Color(*color, os.Stdout)
+	if err != nil {
+		log.Print(err)
+		os.Exit(2)
+	}
+	p := printer{term.Printer{W: os.Stdout, Color: on}}
+	var re *regexp.Regexp
+	if *match != "" {
+		if re, err = regexp.Compile(*match); err != nil {
+			log.Print(err)
+			os.Exit(2)
+		}
+	}
+
+	a, err := load(flag.Arg(0), re)
+	if err != nil {
+		log.Print(err)
+		os.Exit(2)
+	}
+	b, err := load(flag.Arg(1), re)
+	if err != nil {
+		log.Print(err)
+		os.Exit(2)
+	}
+	diffs := assert.DiffFailures(a, b)
+	for _, d := range diffs {
+		p.diff(d)
+	}
+	if len(diffs) > 0 {
+		os.Exit(1)
+	}
+}
+
+var errFound = errors.New("found")
+
+// load returns the first failure in the file name that matches re.
+func load(name string, re *regexp.Regexp) (assert.Failure, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return assert.Failure{}, err
+	}
+	defer f.Close()
+	var found assert.Failure
+	err = assert.ReadFailures(f, func(fl assert.Failure) error {
+		if re != nil && !re.MatchString(fl.Message) && !re.MatchString(fl.ID) && !re.MatchString(fl.Fingerprint) {
+			return nil
+		}
+		found = fl
+		return errFound
+	})
+	switch err {
+	case errFound:
+		return found, nil
+	case nil:
+		return assert.Failure{}, fmt.Errorf("%s: no matching failure", name)
+	}
+	return assert.Failure{}, fmt.Errorf("%s: %w", name, err)
+}
+
+type printer struct {
+	term.Printer
+}
+
+// diff prints d as the lines only in a, prefixed with "-", and those only in
+// b, prefixed with "+". Multi-line values, such as stacks, are compared line
+// by line and shown with the lines they share. Text from the reports goes
+// through term.Clean, as it may carry untrusted input.
+func (p printer) diff(d assert.FailureDiff) {
+	fmt.Fprintf(p.W, "%s (%s)\n", p.Paint(term.Bold, term.Clean(d.Field)), d.Kind)
+	as, bs := lines(term.Clean(d.A)), lines(term.Clean(d.B))
+	for _, l := range lineDiff(as, bs) {
+		switch l.op {
+		case '-':
+			fmt.Fprintln(p.W, p.Paint(term.Red, "  - "+l.text))
+		case '+':
+			fmt.Fprintln(p.W, p.Paint(term.Green, "  + "+l.text))
+		default:
+			fmt.Fprintln(p.W, "    "+l.text)
+		}
+	}
+}
+
+func lines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+type diffLine struct {
+	op   byte // '-', '+' or ' '
+	text string
+}
+
+// lineDiff returns an edit script turning a into b, from their longest
+// common subsequence.
+func lineDiff(a, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffLine{'-', a[i]})
+			i++
+		default:
+			out = append(out, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, diffLine{'+', b[j]})
+	}
+	return out
+}
@@ -0,0 +1,209 @@
+// Command assertexpr records the source text of assertion conditions so that
+// failure reports can show it, like C's assert does:
+//
+//	ASSERT
+//	   msg=buffer overrun
+//	   expression=n < len(buf)
+//
+// Run it from go generate in each package whose assertions it should cover:
+//
+//	//go:generate go run github.com/bhuvneshuchiha/assert/cmd/assertexpr
+//
+// It scans the package's non-test Go files for package-qualified calls to
+// the assertions below and writes assert_expressions.go, which registers the
+// condition text of each call by file and line. Rerun it when assertions
+// move; stale entries only cost a wrong or missing expression line.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const assertPath = "github.com/bhuvneshuchiha/assert"
+
+// conditions maps each assertion to the index of its condition argument.
+var conditions = map[string]int{
+	"Assert":         0,
+	"AssertAttrs":    0,
+	"Check":          0,
+	"Require":        0,
+	"Ensure":         0,
+	"Invariant":      0,
+	"Nil":            0,
+	"NotNil":         0,
+	"NoError":        0,
+	"CheckNil":       0,
+	"CheckNotNil":    0,
+	"CheckNoError":   0,
+	"NotNilPtr":      0,
+	"NotNilMap":      0,
+	"NotNilSlice":    0,
+	"NotNilChan":     0,
+	"NotNilSendChan": 0,
+	"NotNilFunc":     0,
+	"ChanClosed":     0,
+	"ChanNotClosed":  0,
+	"ChanEmpty":      0,
+	"ChanLen":        0,
+	"NotBlank":       0,
+	"AssertCtx":      1,
+	"NilCtx":         1,
+	"NotNilCtx":      1,
+	"NoErrorCtx":     1,
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("assertexpr: ")
+	output := flag.String("o", "assert_expressions.go", "name of the generated file")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if err := generate(dir, *output); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(dir, output string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return name != output && !strings.HasSuffix(name, "_test.go")
+	}, 0)
+	if err != nil {
+		return err
+	}
+	if len(pkgs) != 1 {
+		return fmt.Errorf("%s: found %d packages, want 1", dir, len(pkgs))
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+	exprs := map[string]string{}
+	for name, f := range pkg.Files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		collect(fset, f, src, exprs)
+	}
+	if len(exprs) == 0 {
+		return nil
+	}
+
+	// Runtime frames name functions in package main "main.f", whatever the
+	// command's import path.
+	path := "main"
+	if pkg.Name != "main" {
+		if path, err = importPath(dir); err != nil {
+			return err
+		}
+	}
+	out, err := render(pkg.Name, path, exprs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, output), out, 0o644)
+}
+
+// collect adds the condition of every assertion in f to exprs.
+func collect(fset *token.FileSet, f *ast.File, src []byte, exprs map[string]string) {
+	local := ""
+	for _, imp := range f.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == assertPath {
+			local = "assert"
+			if imp.Name != nil {
+				local = imp.Name.Name
+			}
+		}
+	}
+	if local == "" || local == "_" || local == "." {
+		return
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := call.Fun
+		if ix, ok := fn.(*ast.IndexExpr); ok {
+			fn = ix.X
+		} else if ix, ok := fn.(*ast.IndexListExpr); ok {
+			fn = ix.X
+		}
+		sel, ok := fn.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); !ok || id.Name != local {
+			return true
+		}
+		i, ok := conditions[sel.Sel.Name]
+		if !ok || i >= len(call.Args) {
+			return true
+		}
+
+		arg := call.Args[i]
+		text := string(src[fset.Position(arg.Pos()).Offset:fset.Position(arg.End()).Offset])
+		text = strings.Join(strings.Fields(text), " ")
+		file := filepath.Base(fset.Position(call.Pos()).Filename)
+		// Which line a multi-line call is attributed to depends on the
+		// compiler, so register it at both the start of the call and its
+		// opening parenthesis.
+		for _, pos := range []token.Pos{call.Pos(), call.Lparen} {
+			exprs[fmt.Sprintf("%s:%d", file, fset.Position(pos).Line)] = text
+		}
+		return true
+	})
+}
+
+func importPath(dir string) (string, error) {
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}}", ".")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go list: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func render(name, path string, exprs map[string]string) ([]byte, error) {
+	sites := make([]string, 0, len(exprs))
+	for site := range exprs {
+		sites = append(sites, site)
+	}
+	sort.Strings(sites)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by assertexpr; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "//go:build !tinygo\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", name)
+	fmt.Fprintf(&b, "import %q\n\n", assertPath)
+	fmt.Fprintf(&b, "func init() {\n")
+	fmt.Fprintf(&b, "\tassert.RegisterExpressions(%q, map[string]string{\n", path)
+	for _, site := range sites {
+		fmt.Fprintf(&b, "\t\t%q: %q,\n", site, exprs[site])
+	}
+	fmt.Fprintf(&b, "\t})\n}\n")
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,358 @@
+// Command assertstrip removes assertions from release builds. It rewrites
+// every assertion statement in the given packages to
+//
+//	if false { assert.Assert(n < len(buf), "buffer overrun") }
+//
+// which the compiler drops entirely, and prints the path of an overlay file
+// for go build. Sources on disk are not touched, and line numbers are kept:
+//
+//	go build -overlay="$(go run github.com/bhuvneshuchiha/assert/cmd/assertstrip ./internal/...)" ./cmd/server
+//
+// Stripping an assertion also skips evaluating its arguments, so it refuses
+// to strip, and exits with status 1, if any argument could have a side
+// effect. Arguments may use variables, literals, operators other than
+// receives, indexing, conversions, builtins such as len, function literals
+// and the slog attribute constructors. Calls to other functions known to be
+// pure can be allowed with -pure, which names each by its import path:
+//
+//	assertstrip -pure 'bytes.Equal,example.com/geom.Area' ./...
+//
+// Calls are matched through the file's imports, so a package imported under
+// another name is still recognised. Methods cannot be allowed: telling which
+// type a method belongs to takes type checking.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const assertPath = "github.com/bhuvneshuchiha/assert"
+
+// assertions are the functions that are stripped: every statement-form
+// assertion, one that takes a message and returns nothing. Check* functions
+// return errors the program acts on and are left alone, as are
+// configuration calls such as ReportOnce, which also govern the failures of
+// Watch, Heartbeat and the other checks that keep running.
+var assertions = map[string]bool{
+	"Assert":              true,
+	"AssertAttrs":         true,
+	"AssertCtx":           true,
+	"Require":             true,
+	"Ensure":              true,
+	"Invariant":           true,
+	"Nil":                 true,
+	"NilCtx":              true,
+	"NotNil":              true,
+	"NotNilCtx":           true,
+	"NotNilPtr":           true,
+	"NotNilMap":           true,
+	"NotNilSlice":         true,
+	"NotNilChan":          true,
+	"NotNilSendChan":      true,
+	"NotNilFunc":          true,
+	"NoError":             true,
+	"NoErrorCtx":          true,
+	"Never":               true,
+	"NeverCtx":            true,
+	"CtxAlive":            true,
+	"CtxHasDeadline":      true,
+	"CheckInvariants":     true,
+	"CheckInvariantsDeep": true,
+	"Sampled":             true,
+	"ChanClosed":          true,
+	"ChanNotClosed":       true,
+	"ChanEmpty":           true,
+	"ChanLen":             true,
+	"Before":              true,
+	"After":               true,
+	"NotZeroTime":         true,
+	"Aligned":             true,
+	"PathWithin":          true,
+	"ValidHostPort":       true,
+	"ValidIP":             true,
+	"PortInRange":         true,
+	"NotBlank":            true,
+	"EqualError":          true,
+	"ErrorMatches":        true,
+	"SampledEvery":        true,
+	"Eventually":          true,
+	"HasFlags":            true,
+	"NoFlags":             true,
+	"ExactlyFlags":        true,
+	"WithGroup":           true,
+	"AllClosed":           true,
+	"AllocsUnder":         true,
+	"HeapBelow":           true,
+}
+
+// pure are the calls allowed in arguments besides conversions and builtins,
+// by import path and function name.
+var pure = map[string]bool{
+	"log/slog.Any":      true,
+	"log/slog.Bool":     true,
+	"log/slog.Duration": true,
+	"log/slog.Float64":  true,
+	"log/slog.Group":    true,
+	"log/slog.Int":      true,
+	"log/slog.Int64":    true,
+	"log/slog.String":   true,
+	"log/slog.Time":     true,
+	"log/slog.Uint64":   true,
+	"errors.Is":         true,
+}
+
+var builtins = map[string]bool{
+	"len": true, "cap": true, "min": true, "max": true,
+	"real": true, "imag": true, "complex": true,
+	"bool": true, "string": true, "error": true, "any": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"uintptr": true, "byte": true, "rune": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("assertstrip: ")
+	extra := flag.String("pure", "", "comma-separated `calls` to treat as free of side effects")
+	out := flag.String("o", "", "`dir`ectory for the rewritten files and overlay (default: a new temporary directory)")
+	flag.Parse()
+
+	for _, name := range strings.Split(*extra, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !strings.Contains(name[strings.LastIndexByte(name, '/')+1:], ".") {
+			log.Fatalf("-pure %s: want a package function, as importpath.Func", name)
+		}
+		pure[name] = true
+	}
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	files, err := listFiles(patterns)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dir := *out
+	if dir == "" {
+		if dir, err = os.MkdirTemp("", "assertstrip"); err != nil {
+			log.Fatal(err)
+		}
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	replace := map[string]string{}
+	failed := false
+	fset := token.NewFileSet()
+	for i, file := range files {
+		name := file.path
+		src, err := os.ReadFile(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		stripped, problems, err := strip(fset, file.pkg, name, src)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, p := range problems {
+			fmt.Fprintln(os.Stderr, p)
+			failed = true
+		}
+		if stripped == nil {
+			continue
+		}
+		dst := filepath.Join(dir, fmt.Sprintf("%d_%s", i, filepath.Base(name)))
+		if err := os.WriteFile(dst, stripped, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		replace[name] = dst
+	}
+	if failed {
+		os.Exit(1)
+	}
+
+	overlay, err := json.MarshalIndent(struct{ Replace map[string]string }{replace}, "", "\t")
+	if err != nil {
+		log.Fatal(err)
+	}
+	path := filepath.Join(dir, "overlay.json")
+	if err := os.WriteFile(path, overlay, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(path)
+}
+
+// goFile is a Go file and the import path of its package.
+type goFile struct {
+	pkg, path string
+}
+
+// listFiles returns the Go files in the packages matching patterns, as go
+// build would select them, with their absolute paths.
+func listFiles(patterns []string) ([]goFile, error) {
+	args := append([]string{"list", "-f", "{{$p := .ImportPath}}{{$d := .Dir}}{{range .GoFiles}}{{$p}}\t{{$d}}/{{.}}\n{{end}}"}, patterns...)
+	cmd := exec.Command("go", args...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w", err)
+	}
+	var files []goFile
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if pkg, path, ok := strings.Cut(line, "\t"); ok {
+			files = append(files, goFile{pkg, path})
+		}
+	}
+	return files, nil
+}
+
+// strip returns src, a file of package pkg, with its assertion statements
+// wrapped in "if false", or nil if it has none, and a description of every
+// assertion that cannot be stripped because an argument may have side
+// effects.
+func strip(fset *token.FileSet, pkg, name string, src []byte) ([]byte, []string, error) {
+	f, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil, err
+	}
+	// imports maps the names the file refers to packages by to their import
+	// paths; the file's own package is "".
+	imports := map[string]string{"": pkg}
+	for _, imp := range f.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := p[strings.LastIndexByte(p, '/')+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = p
+	}
+	local := ""
+	for name, p := range imports {
+		if p == assertPath && name != "" {
+			local = name
+		}
+	}
+	if local == "" || local == "_" || local == "." {
+		return nil, nil, nil
+	}
+
+	var stmts []*ast.ExprStmt
+	var problems []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.ExprStmt)
+		if !ok {
+			return true
+		}
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok || !isAssertion(call.Fun, local) {
+			return true
+		}
+		for _, arg := range call.Args {
+			if bad := impure(arg, imports); bad != nil {
+				problems = append(problems, fmt.Sprintf("%s: cannot strip assertion: %s may have side effects",
+					fset.Position(bad.Pos()), source(fset, src, bad)))
+				return false
+			}
+		}
+		stmts = append(stmts, stmt)
+		return false
+	})
+	if len(stmts) == 0 {
+		return nil, problems, nil
+	}
+
+	// Splice in reverse so earlier offsets stay valid. Nothing spans a new
+	// line, so positions in stacks and reports still match the source.
+	out := append([]byte(nil), src...)
+	for i := len(stmts) - 1; i >= 0; i-- {
+		start := fset.Position(stmts[i].Pos()).Offset
+		end := fset.Position(stmts[i].End()).Offset
+		out = append(out[:end], append([]byte(" }"), out[end:]...)...)
+		out = append(out[:start], append([]byte("if false { "), out[start:]...)...)
+	}
+	return out, problems, nil
+}
+
+func isAssertion(fn ast.Expr, local string) bool {
+	switch x := fn.(type) {
+	case *ast.IndexExpr:
+		fn = x.X
+	case *ast.IndexListExpr:
+		fn = x.X
+	}
+	sel, ok := fn.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == local && assertions[sel.Sel.Name]
+}
+
+// impure returns the first part of e that may have a side effect, or nil.
+// imports are the file's imports, as strip collects them.
+func impure(e ast.Expr, imports map[string]string) ast.Node {
+	var bad ast.Node
+	ast.Inspect(e, func(n ast.Node) bool {
+		if bad != nil {
+			return false
+		}
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			// Creating a closure has no effect; calling it is up to the
+			// assertion, which is gone.
+			return false
+		case *ast.UnaryExpr:
+			if x.Op == token.ARROW {
+				bad = x
+			}
+		case *ast.CallExpr:
+			if !pureCall(x.Fun, imports) {
+				bad = x
+			}
+		}
+		return bad == nil
+	})
+	return bad
+}
+
+func pureCall(fn ast.Expr, imports map[string]string) bool {
+	switch x := fn.(type) {
+	case *ast.ParenExpr:
+		return pureCall(x.X, imports)
+	case *ast.Ident:
+		return builtins[x.Name] || pure[imports[""]+"."+x.Name]
+	case *ast.SelectorExpr:
+		// Only package functions: x.X may also be a value, whose methods
+		// cannot be told apart without type checking.
+		id, ok := x.X.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		p, ok := imports[id.Name]
+		return ok && id.Name != "" && pure[p+"."+x.Sel.Name]
+	case *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType, *ast.StarExpr:
+		// Conversions.
+		return true
+	}
+	return false
+}
+
+func source(fset *token.FileSet, src []byte, n ast.Node) string {
+	text := string(src[fset.Position(n.Pos()).Offset:fset.Position(n.End()).Offset])
+	return strings.Join(strings.Fields(text), " ")
+}
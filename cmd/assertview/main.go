@@ -0,0 +1,283 @@
+// Command assertview pretty-prints failure reports written in machine
+// formats: FormatJSON lines, FormatBinary streams and post-mortem files, gzip
+// compressed or not. It colours sections, folds stacks down to the frames
+// that matter and can filter failures and data, for reading reports during
+// incident response:
+//
+//	assertview /var/log/asserts.jsonl
+//	assertview -grep 'ledger' -data '^txn' crashes/assert-20240102T150405.000-4242.json.gz
+//	tail -f asserts.bin | assertview -stack none
+//
+// With no files, or "-", it reads standard input.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bhuvneshuchiha/assert"
+	"github.com/bhuvneshuchiha/assert/internal/term"
+)
+
+const assertPath = "github.com/bhuvneshuchiha/assert"
+
+type options struct {
+	color  bool
+	stack  string // "fold", "full" or "none"
+	frames int
+	grep   *regexp.Regexp
+	data   *regexp.Regexp
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("assertview: ")
+	color := flag.String("color", "auto", `colour output: "auto", "always" or "never"`)
+	stack := flag.String("stack", "fold", `stacks: "fold" to the first frames outside the assert package, "full" or "none"`)
+	frames := flag.Int("frames", 6, "frames kept when folding stacks")
+	grep := flag.String("grep", "", "only show failures whose message, area, site, ID or data match this regexp")
+	data := flag.String("data", "", "only show data whose key matches this regexp")
+	flag.Parse()
+
+	opts := options{stack: *stack, frames: *frames}
+	var err error
+	if opts.color, err = term.Color(*color, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	switch opts.stack {
+	case "fold", "full", "none":
+	default:
+		log.Fatalf("unknown -stack %q", opts.stack)
+	}
+	if *grep != "" {
+		if opts.grep, err = regexp.Compile(*grep); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *data != "" {
+		if opts.data, err = regexp.Compile(*data); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	failed := false
+	for _, name := range files {
+		if err := view(w, name, opts); err != nil {
+			log.Print(err)
+			failed = true
+		}
+	}
+	if failed {
+		w.Flush()
+		os.Exit(1)
+	}
+}
+
+// view renders every failure in the file name, "-" for standard input.
+func view(w io.Writer, name string, opts options) error {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	err := assert.ReadFailures(r, func(f assert.Failure) error {
+		if matches(f, opts.grep) {
+			render(w, f, opts)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func matches(f assert.Failure, re *regexp.Regexp) bool {
+	if re == nil {
+		return true
+	}
+	if re.MatchString(f.Message) || re.MatchString(f.Area) || re.MatchString(f.ID) ||
+		re.MatchString(fmt.Sprintf("%s:%d", f.File, f.Line)) || re.MatchString(f.Function) {
+		return true
+	}
+	for _, v := range f.Data {
+		if re.MatchString(fmt.Sprint(v)) {
+			return true
+		}
+	}
+	for _, e := range slices.Concat(f.Breadcrumbs, f.Logs) {
+		if re.MatchString(e.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// pair is a key/value pair of Failure.Data.
+type pair struct {
+	Key   string
+	Value any
+}
+
+// render writes f to w. Every string taken from f goes through term.Clean:
+// reports carry untrusted input.
+func render(w io.Writer, f assert.Failure, opts options) {
+	p := term.Printer{W: w, Color: opts.color}
+	clean := term.Clean
+
+	sevColor := term.Cyan
+	switch {
+	case f.Severity >= assert.SeverityError:
+		sevColor = term.Red
+	case f.Severity == assert.SeverityWarn:
+		sevColor = term.Yellow
+	}
+	fmt.Fprintf(w, "%s %s\n", p.Paint(term.Bold+sevColor, strings.ToUpper(f.Severity.String())), p.Paint(term.Bold, clean(f.Message)))
+	site := clean(fmt.Sprintf("%s:%d", f.File, f.Line))
+	if f.Function != "" {
+		site += " " + p.Paint(term.Dim, "("+clean(f.Function)+")")
+	}
+	fmt.Fprintf(w, "  %s %s\n", p.Paint(term.Blue, "at"), site)
+
+	var meta []string
+	if !f.Time.IsZero() {
+		meta = append(meta, f.Time.Format("2006-01-02 15:04:05.000 MST"))
+	}
+	meta = append(meta, "area="+f.Area, "mode="+f.Mode.String())
+	if f.ID != "" {
+		meta = append(meta, "id="+f.ID)
+	}
+	if f.Fingerprint != "" {
+		meta = append(meta, "fingerprint="+f.Fingerprint)
+	}
+	if f.Suppressed > 0 {
+		meta = append(meta, "suppressed="+strconv.FormatUint(f.Suppressed, 10))
+	}
+	fmt.Fprintf(w, "  %s\n", p.Paint(term.Dim, clean(strings.Join(meta, "  "))))
+	if f.Expression != "" {
+		fmt.Fprintf(w, "  %s %s\n", p.Paint(term.Blue, "expression"), clean(f.Expression))
+	}
+
+	var data []pair
+	for i := 0; i+1 < len(f.Data); i += 2 {
+		a := pair{fmt.Sprint(f.Data[i]), f.Data[i+1]}
+		if opts.data == nil || opts.data.MatchString(a.Key) {
+			data = append(data, a)
+		}
+	}
+	if len(data) > 0 {
+		fmt.Fprintf(w, "\n  %s\n", p.Paint(term.Bold+term.Blue, "data"))
+		width := 0
+		for _, a := range data {
+			width = max(width, len(clean(a.Key)))
+		}
+		for _, a := range data {
+			v := fmt.Sprint(a.Value)
+			if err, ok := a.Value.(error); ok {
+				v = err.Error()
+			}
+			v = strings.ReplaceAll(strings.TrimRight(clean(v), "\n"), "\n", "\n    "+strings.Repeat(" ", width+3))
+			fmt.Fprintf(w, "    %s = %s\n", p.Paint(term.Cyan, fmt.Sprintf("%-*s", width, clean(a.Key))), v)
+		}
+	}
+
+	events := func(name string, events []assert.Event) {
+		if len(events) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n  %s\n", p.Paint(term.Bold+term.Blue, name))
+		for _, e := range events {
+			fmt.Fprintf(w, "    %s\n", clean(e.String()))
+		}
+	}
+	events("breadcrumbs", f.Breadcrumbs)
+	events("logs", f.Logs)
+	if f.SimSeed != nil {
+		fmt.Fprintf(w, "\n  %s %d\n", p.Paint(term.Bold+term.Blue, "simulation seed"), *f.SimSeed)
+	}
+	if pr := f.Process; pr != nil {
+		fmt.Fprintf(w, "\n  %s\n", p.Paint(term.Bold+term.Blue, "process"))
+		fmt.Fprintf(w, "    %s\n", clean(fmt.Sprintf("pid %d on %s, %s %s/%s", pr.PID, pr.Hostname, pr.GoVersion, pr.GOOS, pr.GOARCH)))
+		fmt.Fprintf(w, "    %s\n", clean(strings.Join(pr.Args, " ")))
+	}
+
+	if f.Stack != "" && opts.stack != "none" {
+		fmt.Fprintf(w, "\n  %s\n", p.Paint(term.Bold+term.Blue, "stack"))
+		stack := clean(f.Stack)
+		if opts.stack == "fold" {
+			stack = foldStack(stack, opts.frames, p)
+		}
+		for _, line := range strings.Split(strings.TrimRight(stack, "\n"), "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// foldStack keeps each goroutine's header and its first n frames outside
+// the assert package and the runtime's stack printing, summarising the rest.
+func foldStack(stack string, n int, p term.Printer) string {
+	lines := strings.Split(strings.TrimRight(stack, "\n"), "\n")
+	var out []string
+	hidden, kept, dropped := 0, 0, 0
+	summarise := func() {
+		if dropped > 0 {
+			out = append(out, p.Paint(term.Dim, fmt.Sprintf("… %d more frames", dropped)))
+		}
+		hidden, kept, dropped = 0, 0, 0
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if !strings.HasPrefix(line, "\t") && (i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "\t")) {
+			// A goroutine header or a line that is not part of a frame.
+			if strings.HasPrefix(line, "goroutine ") {
+				summarise()
+			}
+			out = append(out, line)
+			continue
+		}
+		frame := []string{line}
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+			frame = append(frame, lines[i+1])
+			i++
+		}
+		switch {
+		case internalFrame(line):
+			hidden++
+		case kept < n:
+			if hidden > 0 && kept == 0 {
+				out = append(out, p.Paint(term.Dim, fmt.Sprintf("… %d assert frames", hidden)))
+			}
+			out = append(out, frame...)
+			kept++
+		default:
+			dropped++
+		}
+	}
+	summarise()
+	return strings.Join(out, "\n")
+}
+
+func internalFrame(fn string) bool {
+	return strings.HasPrefix(fn, "runtime/debug.") ||
+		strings.HasPrefix(fn, assertPath+".") ||
+		strings.HasPrefix(fn, assertPath+"/")
+}
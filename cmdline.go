@@ -0,0 +1,66 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IncludeCommandLine controls whether reports include the command line the
+// process was started with and its working directory, since the same binary
+// behaves differently per invocation:
+//
+//	cmdline=/usr/bin/indexer -shard 3 -config "/etc/indexer/prod config.json"
+//	cwd=/srv/indexer
+//
+// Arguments pass through the function set with ScrubArgs first. It is off by
+// default.
+func IncludeCommandLine(on bool) {
+	updateConfig(func(c *config) { c.cmdline = on })
+}
+
+// ScrubArgs sets a function that rewrites the command-line arguments before
+// they are reported, by IncludeCommandLine or in post-mortem files, e.g. to
+// redact credentials passed as flags. fn receives a copy of os.Args. A nil
+// fn reports the arguments as they are.
+//
+//	assert.ScrubArgs(func(args []string) []string {
+//		for i, a := range args {
+//			if strings.HasPrefix(a, "-password=") {
+//				args[i] = "-password=REDACTED"
+//			}
+//		}
+//		return args
+//	})
+func ScrubArgs(fn func(args []string) []string) {
+	updateConfig(func(c *config) { c.scrubArgs = fn })
+}
+
+// reportedArgs returns os.Args after the scrubber.
+func (c *config) reportedArgs() []string {
+	args := slices.Clone(os.Args)
+	if c.scrubArgs != nil {
+		args = c.scrubArgs(args)
+	}
+	return args
+}
+
+// commandLinePairs returns the command line and working directory.
+func commandLinePairs(c *config) []any {
+	args := c.reportedArgs()
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = a
+		if a == "" || strings.ContainsAny(a, " \t\n\"'\\") {
+			quoted[i] = strconv.Quote(a)
+		}
+	}
+	pairs := []any{"cmdline", strings.Join(quoted, " ")}
+	if wd, err := os.Getwd(); err == nil {
+		pairs = append(pairs, "cwd", wd)
+	}
+	return pairs
+}
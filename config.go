@@ -0,0 +1,131 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// config is an immutable snapshot of the package configuration. Setters copy
+// the current snapshot, change the copy and publish it atomically, so a
+// failing assertion reads one consistent configuration without taking a lock
+// and never races with a concurrent setter.
+type config struct {
+	disabled       bool
+	mode           Mode
+	minSeverity    Severity
+	writer         io.Writer
+	stack          bool
+	exit           func(code int)
+	crashDir       string
+	minidump       bool
+	labels         bool
+	terminationLog string
+	journal        *journalConn
+	eventLog       *eventLog
+	format         Format
+	breakMode      BreakMode
+	interactive    bool
+	postMortem     bool
+	compressCrash  bool
+	encrypt        func(io.Writer) (io.WriteCloser, error)
+	encryptExt     string
+	policy         []Rule
+	suppressions   []Suppression
+	routes         map[string]io.Writer
+	syncOnExit     bool
+	resources      bool
+	env            []string // patterns of CaptureEnv
+	cmdline        bool
+	scrubArgs      func(args []string) []string
+	cgroup         bool
+	sched          bool
+	runtimeMetrics []string // samples of IncludeRuntimeMetrics
+	flushTimeout   time.Duration
+}
+
+var configMu sync.Mutex
+
+// currentConfig is set during variable initialisation, before any init
+// function can change it, so loadConfig never sees nil.
+var currentConfig = newConfigPointer(&config{
+	mode:         defaultMode,
+	minSeverity:  SeverityError,
+	stack:        true,
+	exit:         os.Exit,
+	flushTimeout: defaultExitFlushTimeout,
+})
+
+func newConfigPointer(c *config) *atomic.Pointer[config] {
+	p := new(atomic.Pointer[config])
+	p.Store(c)
+	return p
+}
+
+// loadConfig returns the current configuration. The result must not be
+// modified.
+func loadConfig() *config {
+	return currentConfig.Load()
+}
+
+// updateConfig publishes a copy of the current configuration changed by fn.
+func updateConfig(fn func(c *config)) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	next := *loadConfig()
+	fn(&next)
+	currentConfig.Store(&next)
+}
+
+func (c *config) output() io.Writer {
+	if c.writer == nil {
+		return defaultOutput()
+	}
+	return c.writer
+}
+
+// write sends r to the journal if ToJournal is in effect and to the output
+// routed for its area otherwise.
+func (c *config) write(r *report) {
+	if c.journal != nil && c.journal.send(r) == nil {
+		return
+	}
+	r.writeTo(c.outputFor(r.area), c.format)
+}
+
+// modeFor returns the mode that applies to a failure in area with severity
+// s, and false if the policy says to ignore it.
+func (c *config) modeFor(area string, s Severity) (Mode, bool) {
+	if len(c.policy) > 0 {
+		if action, rate := c.policyAction(area, s); action != ActionDefault {
+			return actionMode(action, rate)
+		}
+	}
+	if s < c.minSeverity {
+		return ModeWarn, true
+	}
+	return c.mode, true
+}
+
+// actionMode returns the mode for a failure a policy or suppression gives
+// action, and false if the failure is to be ignored.
+func actionMode(action Action, rate float64) (Mode, bool) {
+	switch action {
+	case ActionExit:
+		return ModeExit, true
+	case ActionPanic:
+		return ModePanic, true
+	case ActionGoexit:
+		return ModeGoexit, true
+	case ActionSample:
+		return ModeWarn, rate >= 1 || rate > 0 && randFloat64() < rate
+	case ActionIgnore:
+		return ModeWarn, false
+	}
+	return ModeWarn, true
+}
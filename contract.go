@@ -0,0 +1,67 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Require asserts a precondition of the calling function.
+//
+//	func (q *Queue) Pop() Item {
+//		assert.Require(q.Len() > 0, "pop from empty queue")
+func Require(cond bool, msg string, data ...any) {
+	if !cond || tracking.Load() {
+		contract("precondition", !cond, msg, data)
+	}
+}
+
+// Ensure asserts a postcondition of the calling function. Call it from a
+// deferred closure to check named results after the function returns:
+//
+//	func (q *Queue) Push(it Item) (n int) {
+//		defer func() { assert.Ensure(n == q.Len(), "length not updated", "n", n) }()
+func Ensure(cond bool, msg string, data ...any) {
+	if !cond || tracking.Load() {
+		contract("postcondition", !cond, msg, data)
+	}
+}
+
+// Invariant asserts a condition that must hold whenever the calling code is
+// between operations, such as a type's internal consistency.
+func Invariant(cond bool, msg string, data ...any) {
+	if !cond || tracking.Load() {
+		contract("invariant", !cond, msg, data)
+	}
+}
+
+func contract(kind string, failed bool, msg string, data []any) {
+	if !failed {
+		passed(msg, data)
+		return
+	}
+	fn := enclosingFunc(failureFrame().Function)
+	runAssert(msg, append(data, "contract", kind, "func", fn)...)
+}
+
+// closureSuffix matches the suffixes the compiler gives closures and
+// deferred or go wrappers, e.g. "Push.func1" or "Push.deferwrap1".
+var closureSuffix = regexp.MustCompile(`\.(func|deferwrap|gowrap)\d+$`)
+
+// enclosingFunc returns the name of the function a closure is declared in,
+// without its import path, so an Ensure in a deferred closure is reported
+// against the function it guards.
+func enclosingFunc(name string) string {
+	for {
+		trimmed := closureSuffix.ReplaceAllString(name, "")
+		if trimmed == name {
+			break
+		}
+		name = trimmed
+	}
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,165 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SetCrashDir makes fatal failures also write their report to a file in dir,
+// named after the failure time and process ID, e.g.
+// "assert-20240102T150405.000-4242.txt". Other crash artifacts are written
+// next to it under the same name, readable only by the process's user. The
+// directory is created if needed. An empty dir, the default, disables crash
+// files.
+func SetCrashDir(dir string) {
+	updateConfig(func(c *config) { c.crashDir = dir })
+}
+
+// IncludeMinidump controls whether fatal failures write a minidump of the
+// process next to the report in the crash directory, for loading into a
+// debugger. Minidumps are only supported on Windows and are off by default.
+func IncludeMinidump(on bool) {
+	updateConfig(func(c *config) { c.minidump = on })
+}
+
+// CompressCrashFiles controls whether the text report and post-mortem file
+// written to the crash directory are gzip compressed, with a ".gz" suffix.
+// Reports with large AssertData dumps and every goroutine's stack compress
+// well. LoadReport reads compressed post-mortem files as they are. It is off
+// by default.
+func CompressCrashFiles(on bool) {
+	updateConfig(func(c *config) { c.compressCrash = on })
+}
+
+// EncryptCrashFiles makes fatal failures encrypt the files they write to the
+// crash directory, since reports and traces often hold customer data that
+// must not sit on disk in plaintext. wrap returns a writer that encrypts what
+// is written to w, finishing the ciphertext when closed, and ext is appended
+// to the names of encrypted files. Compressed files are compressed before
+// they are encrypted. Minidumps, which hold the process memory, are not
+// written while encryption is on. A nil wrap turns encryption off.
+//
+// See the assertage module for encryption to age recipients.
+func EncryptCrashFiles(ext string, wrap func(w io.Writer) (io.WriteCloser, error)) {
+	updateConfig(func(c *config) {
+		c.encryptExt = ext
+		c.encrypt = wrap
+	})
+}
+
+// writeCrashFiles writes the crash artifacts of a fatal failure. Errors are
+// reported on the configured output since the process is about to exit.
+func writeCrashFiles(c *config, r *report) {
+	if c.crashDir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.crashDir, 0o755); err != nil {
+		crashError(c, err)
+		return
+	}
+	base := filepath.Join(c.crashDir, fmt.Sprintf("assert-%s-%d", r.time.Format("20060102T150405.000"), os.Getpid()))
+
+	f, err := c.createCrashFile(base+".txt", c.compressCrash)
+	if err == nil {
+		r.writeTo(f, FormatText)
+		err = f.Close()
+	}
+	if err != nil {
+		crashError(c, err)
+	}
+
+	if c.postMortem {
+		if err := writePostMortem(c, base+".json", r); err != nil {
+			crashError(c, err)
+		}
+	}
+	create := func(path string) (io.WriteCloser, error) { return c.createCrashFile(path, false) }
+	if err := writeFlightRecording(base+".trace", create); err != nil {
+		crashError(c, err)
+	}
+	if c.minidump && c.encrypt == nil {
+		if err := writeMinidump(base + ".dmp"); err != nil {
+			crashError(c, err)
+		}
+	}
+}
+
+// createCrashFile creates the crash file at path, gzip compressed with ".gz"
+// appended if compress is set, and encrypted with the extension set by
+// EncryptCrashFiles appended if encryption is on.
+func (c *config) createCrashFile(path string, compress bool) (io.WriteCloser, error) {
+	if compress {
+		path += ".gz"
+	}
+	if c.encrypt != nil {
+		path += c.encryptExt
+	}
+	// Reports carry data values and environment: only the owner may read
+	// them.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	w := &layeredFile{Writer: f, closers: []io.Closer{f}}
+	if c.encrypt != nil {
+		ew, err := c.encrypt(f)
+		if err != nil {
+			f.Close()
+			os.Remove(path)
+			return nil, err
+		}
+		w.push(ew)
+	}
+	if compress {
+		w.push(gzip.NewWriter(w.Writer))
+	}
+	return w, nil
+}
+
+// layeredFile writes through a stack of writers over a file and closes them
+// from the outermost in, syncing the file before closing it so that the
+// crash files of a process about to exit reach the disk.
+type layeredFile struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (l *layeredFile) push(w io.WriteCloser) {
+	l.Writer = w
+	l.closers = append(l.closers, w)
+}
+
+func (l *layeredFile) Close() error {
+	var err error
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		if f, ok := l.closers[i].(*os.File); ok {
+			if serr := f.Sync(); err == nil {
+				err = serr
+			}
+		}
+		if cerr := l.closers[i].Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func crashError(c *config, err error) {
+	fmt.Fprintf(c.output(), "assert: writing crash files: %v\n", err)
+}
+
+// writeEventLog records a fatal failure in the Windows Event Log if
+// ToEventLog is in effect.
+func writeEventLog(c *config, r *report) {
+	if c.eventLog == nil {
+		return
+	}
+	if err := c.eventLog.record(r); err != nil {
+		fmt.Fprintf(c.output(), "assert: writing to the event log: %v\n", err)
+	}
+}
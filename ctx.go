@@ -0,0 +1,135 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type contextKey struct {
+	name string
+	key  any
+}
+
+var ctxMu sync.RWMutex
+var ctxKeys []contextKey
+var ctxFuncs []func(ctx context.Context) []any
+
+// RegisterContextKey makes the Ctx assertions report ctx.Value(key) under
+// name whenever the context carries it, e.g. a request or tenant ID.
+func RegisterContextKey(name string, key any) {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+	ctxKeys = append(ctxKeys, contextKey{name: name, key: key})
+}
+
+// RegisterContextFunc registers fn to extract key/value pairs from the
+// context of a failed Ctx assertion. Use it for values that are not stored
+// under a plain key, such as the trace and span IDs of a tracing library.
+func RegisterContextFunc(fn func(ctx context.Context) []any) {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+	ctxFuncs = append(ctxFuncs, fn)
+}
+
+// contextData returns data followed by the pairs harvested from ctx.
+func contextData(ctx context.Context, data []any) []any {
+	if ctx == nil {
+		return data
+	}
+	ctxMu.RLock()
+	defer ctxMu.RUnlock()
+
+	for _, k := range ctxKeys {
+		if v := ctx.Value(k.key); v != nil {
+			data = append(data, k.name, v)
+		}
+	}
+	for _, fn := range ctxFuncs {
+		data = append(data, fn(ctx)...)
+	}
+	return data
+}
+
+// AssertCtx is Assert with the values registered through RegisterContextKey
+// and RegisterContextFunc harvested from ctx into the report.
+func AssertCtx(ctx context.Context, truth bool, msg string, data ...any) {
+	if !truth {
+		runAssert(msg, contextData(ctx, data)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// NilCtx is Nil with values harvested from ctx.
+func NilCtx(ctx context.Context, item any, msg string, data ...any) {
+	if item != nil {
+		runAssert(msg, contextData(ctx, data)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// NotNilCtx is NotNil with values harvested from ctx.
+func NotNilCtx(ctx context.Context, item any, msg string, data ...any) {
+	if Enabled() && isNil(item) {
+		runAssert(msg, contextData(ctx, data)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// NoErrorCtx is NoError with values harvested from ctx.
+func NoErrorCtx(ctx context.Context, err error, msg string, data ...any) {
+	if err != nil {
+		runAssert(msg, contextData(ctx, append(data, "error", err))...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// NeverCtx is Never with values harvested from ctx.
+func NeverCtx(ctx context.Context, msg string, data ...any) {
+	runAssert(msg, contextData(ctx, data)...)
+}
+
+// CtxAlive asserts that ctx has not been canceled and its deadline has not
+// passed, guarding code that must not run after cancellation. The report
+// includes the context's error, its cause and how long ago the deadline
+// passed.
+func CtxAlive(ctx context.Context, msg string, data ...any) {
+	if err := ctx.Err(); err != nil {
+		runAssert(msg, contextData(ctx, append(data, ctxState(ctx, err)...))...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// CtxHasDeadline asserts that ctx carries a deadline, for calls that must
+// not be able to block forever.
+func CtxHasDeadline(ctx context.Context, msg string, data ...any) {
+	if _, ok := ctx.Deadline(); !ok {
+		runAssert(msg, contextData(ctx, append(data, ctxState(ctx, ctx.Err())...))...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// ctxState describes ctx's error and deadline for a report.
+func ctxState(ctx context.Context, err error) []any {
+	var state []any
+	if err != nil {
+		state = append(state, "ctx.err", err)
+		if cause := context.Cause(ctx); cause != nil && cause != err {
+			state = append(state, "ctx.cause", cause)
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		state = append(state, "ctx.deadline", deadline.Format(time.RFC3339Nano), "ctx.remaining", time.Until(deadline).String())
+	} else {
+		state = append(state, "ctx.deadline", "none")
+	}
+	return state
+}
@@ -0,0 +1,79 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// BreakMode controls whether failures stop in a debugger.
+type BreakMode int
+
+const (
+	// BreakNever never stops in a debugger. This is the default.
+	BreakNever BreakMode = iota
+	// BreakIfAttached stops in the debugger if one is attached, and otherwise
+	// handles failures as usual.
+	BreakIfAttached
+	// BreakAlways stops in a debugger, first waiting for one to attach if
+	// none is. Only meant for local development.
+	BreakAlways
+)
+
+func (b BreakMode) String() string {
+	switch b {
+	case BreakNever:
+		return "never"
+	case BreakIfAttached:
+		return "attached"
+	case BreakAlways:
+		return "always"
+	}
+	return fmt.Sprintf("BreakMode(%d)", int(b))
+}
+
+// ParseBreakMode parses the name of a break mode as returned by
+// BreakMode.String. Booleans are accepted too, true meaning BreakAlways.
+func ParseBreakMode(s string) (BreakMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "never", "0", "false":
+		return BreakNever, nil
+	case "attached":
+		return BreakIfAttached, nil
+	case "always", "1", "true":
+		return BreakAlways, nil
+	}
+	return BreakNever, fmt.Errorf("assert: unknown break mode %q", s)
+}
+
+// SetBreakMode makes failures stop in a debugger, with runtime.Breakpoint,
+// instead of exiting or panicking, so the live state can be inspected. The
+// report is written first. When the debugger resumes, the failed assertion
+// returns to its caller. Attached debuggers are detected on Linux and
+// Windows; elsewhere BreakIfAttached never breaks and BreakAlways breaks
+// without waiting.
+func SetBreakMode(b BreakMode) {
+	updateConfig(func(c *config) { c.breakMode = b })
+}
+
+// debugBreak writes r and stops in a debugger if c asks for it, reporting
+// whether it did.
+func debugBreak(c *config, r *report) bool {
+	attached, known := debuggerAttached()
+	if !attached && c.breakMode != BreakAlways {
+		return false
+	}
+	c.write(r)
+	if !attached && known {
+		fmt.Fprintf(c.output(), "assert: waiting for a debugger to attach to process %d\n", os.Getpid())
+		for attached, _ = debuggerAttached(); !attached; attached, _ = debuggerAttached() {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	runtime.Breakpoint()
+	return true
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !windows && !tinygo
+
+package assert
+
+func debuggerAttached() (attached, known bool) {
+	return false, false
+}
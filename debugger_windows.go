@@ -0,0 +1,17 @@
+//go:build !tinygo
+
+package assert
+
+import "syscall"
+
+var procIsDebuggerPresent = syscall.NewLazyDLL("kernel32.dll").NewProc("IsDebuggerPresent")
+
+// debuggerAttached reports whether a debugger is attached, and whether that
+// could be determined.
+func debuggerAttached() (attached, known bool) {
+	if procIsDebuggerPresent.Find() != nil {
+		return false, false
+	}
+	r, _, _ := procIsDebuggerPresent.Call()
+	return r != 0, true
+}
@@ -0,0 +1,33 @@
+//go:build !tinygo
+
+package assert
+
+import "runtime"
+
+// DumpState writes the registered assert data, recent breadcrumbs and the
+// stacks of all goroutines to the configured output, using the same report
+// format as a failure but without failing. It answers "what is this process
+// doing right now?"; DumpOnSignal triggers it from outside the process. While
+// TrackSites is on, the recorded assertion sites follow the report.
+func DumpState() {
+	c := loadConfig()
+	r := newReport("state dump", []any{SeverityKey, SeverityDebug})
+	r.collect(&config{})
+	r.stack = allStacks()
+	c.write(r)
+	if tracking.Load() {
+		WriteSites(c.output())
+	}
+}
+
+// allStacks returns the stacks of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,27 @@
+//go:build unix && !tinygo
+
+package assert
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var dumpSignalOnce sync.Once
+
+// DumpOnSignal makes the process call DumpState whenever it receives
+// SIGUSR1, e.g. from "kill -USR1 <pid>". It is only available on Unix
+// systems; calling it more than once has no further effect.
+func DumpOnSignal() {
+	dumpSignalOnce.Do(func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGUSR1)
+		go func() {
+			for range ch {
+				DumpState()
+			}
+		}()
+	})
+}
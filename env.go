@@ -0,0 +1,183 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables read when the package is initialised.
+const (
+	EnvEnabled        = "ASSERT_ENABLED"         // boolean, e.g. "false" disables assertions
+	EnvMode           = "ASSERT_MODE"            // "exit", "panic", "warn" or "goexit"
+	EnvOutput         = "ASSERT_OUTPUT"          // "stderr", "stdout" or a file path to append to
+	EnvStack          = "ASSERT_STACK"           // boolean, "false" omits stacks from reports
+	EnvSeverity       = "ASSERT_SEVERITY"        // least severe failure that is enforced, e.g. "fatal"
+	EnvCrashDir       = "ASSERT_CRASH_DIR"       // directory fatal failures write crash files to
+	EnvCrashCompress  = "ASSERT_CRASH_COMPRESS"  // boolean, "true" gzips crash files
+	EnvTerminationLog = "ASSERT_TERMINATION_LOG" // file fatal failures write a summary to, e.g. "/dev/termination-log"
+	EnvFormat         = "ASSERT_FORMAT"          // "text", "gcp", "json" or "binary"
+	EnvDebug          = "ASSERT_DEBUG"           // "1" waits for and breaks into a debugger, "attached" only breaks into an attached one
+	EnvInteractive    = "ASSERT_INTERACTIVE"     // boolean, "true" asks on the terminal what to do after a failure
+	EnvChaos          = "ASSERT_CHAOS"           // probability that a ChaosPoint fails, e.g. "0.001"
+	EnvChaosSeed      = "ASSERT_CHAOS_SEED"      // seed for ASSERT_CHAOS, random if unset
+	EnvPolicy         = "ASSERT_POLICY"          // policy file to load, see LoadPolicy
+	EnvSuppressions   = "ASSERT_SUPPRESSIONS"    // suppression list to load, see LoadSuppressions
+	EnvCaptureEnv     = "ASSERT_CAPTURE_ENV"     // comma-separated variables to include in reports, see CaptureEnv
+	EnvSettings       = "ASSERT_SETTINGS"        // settings file to load, see LoadSettingsFile
+)
+
+func init() {
+	loadEnv()
+}
+
+// loadEnv applies configuration from the environment. Invalid values are
+// reported on stderr and otherwise ignored, since there is nobody to return
+// an error to during init.
+func loadEnv() {
+	if v, ok := os.LookupEnv(EnvEnabled); ok {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			envError(EnvEnabled, err)
+		} else if on {
+			Enable()
+		} else {
+			Disable()
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvMode); ok {
+		m, err := ParseMode(v)
+		if err != nil {
+			envError(EnvMode, err)
+		} else {
+			SetMode(m)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvOutput); ok {
+		switch v {
+		case "", "stderr":
+			ToWriter(os.Stderr)
+		case "stdout":
+			ToWriter(os.Stdout)
+		default:
+			f, err := os.OpenFile(v, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+			if err != nil {
+				envError(EnvOutput, err)
+			} else {
+				ToWriter(f)
+			}
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvStack); ok {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			envError(EnvStack, err)
+		} else {
+			IncludeStack(on)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvSeverity); ok {
+		s, err := ParseSeverity(v)
+		if err != nil {
+			envError(EnvSeverity, err)
+		} else {
+			SetMinSeverity(s)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvFormat); ok {
+		f, err := ParseFormat(v)
+		if err != nil {
+			envError(EnvFormat, err)
+		} else {
+			SetFormat(f)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvDebug); ok {
+		b, err := ParseBreakMode(v)
+		if err != nil {
+			envError(EnvDebug, err)
+		} else {
+			SetBreakMode(b)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvInteractive); ok {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			envError(EnvInteractive, err)
+		} else {
+			SetInteractive(on)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvChaos); ok {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			envError(EnvChaos, err)
+		} else {
+			var seed uint64
+			if v, ok := os.LookupEnv(EnvChaosSeed); ok {
+				if seed, err = strconv.ParseUint(v, 10, 64); err != nil {
+					envError(EnvChaosSeed, err)
+				}
+			}
+			EnableChaos(rate, seed)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvCrashDir); ok {
+		SetCrashDir(v)
+	}
+
+	if v, ok := os.LookupEnv(EnvCrashCompress); ok {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			envError(EnvCrashCompress, err)
+		} else {
+			CompressCrashFiles(on)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvTerminationLog); ok {
+		SetTerminationLog(v)
+	}
+
+	if v := os.Getenv(EnvPolicy); v != "" {
+		if err := LoadPolicy(v); err != nil {
+			envError(EnvPolicy, err)
+		}
+	}
+
+	if v := os.Getenv(EnvSuppressions); v != "" {
+		if err := LoadSuppressions(v); err != nil {
+			envError(EnvSuppressions, err)
+		}
+	}
+
+	if v := os.Getenv(EnvCaptureEnv); v != "" {
+		var patterns []string
+		for _, p := range strings.Split(v, ",") {
+			patterns = append(patterns, strings.TrimSpace(p))
+		}
+		CaptureEnv(patterns...)
+	}
+
+	if v := os.Getenv(EnvSettings); v != "" {
+		if err := LoadSettingsFile(v); err != nil {
+			envError(EnvSettings, err)
+		}
+	}
+}
+
+func envError(name string, err error) {
+	fmt.Fprintf(os.Stderr, "assert: ignoring %s: %v\n", name, err)
+}
@@ -0,0 +1,42 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// CaptureEnv makes reports include the environment variables whose names
+// match one of patterns, for deployment context such as the release or
+// region, without dumping the whole environment and the secrets in it:
+//
+//	assert.CaptureEnv("DEPLOY_ID", "REGION", "FEATURE_*")
+//
+// Patterns are names or path.Match patterns. Variables are read when a
+// failure is reported and appear as "env.NAME" pairs in name order. Calling
+// CaptureEnv again replaces the patterns; calling it without any stops the
+// capture.
+func CaptureEnv(patterns ...string) {
+	patterns = slices.Clone(patterns)
+	updateConfig(func(c *config) { c.env = patterns })
+}
+
+// envPairs returns the environment variables matching patterns.
+func envPairs(patterns []string) []any {
+	env := os.Environ()
+	slices.Sort(env)
+	var pairs []any
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, name); ok {
+				pairs = append(pairs, "env."+name, value)
+				break
+			}
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,82 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"slices"
+	"time"
+)
+
+// AssertionError describes a failed assertion. It is returned by the Check
+// functions and is the value failed assertions panic with in ModePanic, so
+// recovery code can inspect the failure with errors.As.
+type AssertionError struct {
+	r       *report
+	written bool // the report was written when the assertion failed
+}
+
+func (e *AssertionError) Error() string {
+	return "assertion failed: " + e.r.summary()
+}
+
+// Unwrap returns the error passed to NoError or CheckNoError, if any.
+func (e *AssertionError) Unwrap() error {
+	for i := 0; i+1 < len(e.r.args); i += 2 {
+		if e.r.args[i] == "error" {
+			if err, ok := e.r.args[i+1].(error); ok {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Message returns the assertion's message.
+func (e *AssertionError) Message() string {
+	return e.r.msg
+}
+
+// Area returns the area the assertion was tagged with.
+func (e *AssertionError) Area() string {
+	return e.r.area
+}
+
+// Severity returns the assertion's severity.
+func (e *AssertionError) Severity() Severity {
+	return e.r.severity
+}
+
+// Data returns the key/value pairs passed to the assertion followed by the
+// failing goroutine's pprof labels and the dumps of the registered
+// AssertData.
+func (e *AssertionError) Data() []any {
+	data := make([]any, 0, len(e.r.args)+len(e.r.labels)+len(e.r.dumps))
+	data = append(data, e.r.args...)
+	data = append(data, e.r.labels...)
+	return append(data, e.r.dumps...)
+}
+
+// Stack returns the stack of the failing goroutine, or nil if stacks are
+// disabled with IncludeStack.
+func (e *AssertionError) Stack() []byte {
+	return e.r.stack
+}
+
+// Time returns when the assertion failed.
+func (e *AssertionError) Time() time.Time {
+	return e.r.time
+}
+
+// Report writes the failure's report to the configured output, with data
+// appended to the assertion's own key/value pairs. It is how failures
+// recovered by Guarded, or returned by the Check functions, are reported.
+// Failures whose report was written when they happened, such as panics
+// outside Guarded and warnings, are not written again.
+func (e *AssertionError) Report(data ...any) {
+	if e.written {
+		return
+	}
+	r := *e.r
+	r.args = append(slices.Clip(r.args), data...)
+	loadConfig().write(&r)
+}
@@ -0,0 +1,87 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// EqualError asserts that err is not nil and its message is exactly want,
+// for invariants over the error text an API returns. When it is not, the
+// report includes the whole chain of wrapped errors with their types.
+func EqualError(err error, want string, msg string, data ...any) {
+	if err == nil || err.Error() != want {
+		runAssert(msg, append(data, "error.want", want, "error.chain", errorChain(err))...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// maxCachedPatterns bounds how many compiled patterns ErrorMatches keeps.
+const maxCachedPatterns = 256
+
+var patterns sync.Map // pattern string to *regexp.Regexp
+var patternCount atomic.Int32
+
+// ErrorMatches asserts that err is not nil and its message matches the
+// regular expression pattern. Compiled patterns are cached, so checking the
+// same pattern again does not compile it again.
+func ErrorMatches(err error, pattern string, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	re, perr := compilePattern(pattern)
+	if perr != nil {
+		runAssert(msg, append(data, "error.pattern", pattern, "error", perr)...)
+	} else if err == nil || !re.MatchString(err.Error()) {
+		runAssert(msg, append(data, "error.pattern", pattern, "error.chain", errorChain(err))...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// compilePattern compiles pattern, or returns it from the cache.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err == nil && patternCount.Load() < maxCachedPatterns {
+		if _, loaded := patterns.LoadOrStore(pattern, re); !loaded {
+			patternCount.Add(1)
+		}
+	}
+	return re, err
+}
+
+// errorChain describes err and every error it wraps, one per line and
+// indented by depth, with their types.
+func errorChain(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	var b strings.Builder
+	var walk func(err error, depth int)
+	walk = func(err error, depth int) {
+		if depth > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s%T: %q", strings.Repeat("  ", depth), err, err)
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			if next := u.Unwrap(); next != nil {
+				walk(next, depth+1)
+			}
+		case interface{ Unwrap() []error }:
+			for _, next := range u.Unwrap() {
+				walk(next, depth+1)
+			}
+		}
+	}
+	walk(err, 0)
+	return b.String()
+}
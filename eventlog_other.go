@@ -0,0 +1,17 @@
+//go:build !windows && !tinygo
+
+package assert
+
+import "errors"
+
+type eventLog struct{}
+
+// ToEventLog records fatal failures in the Windows Event Log. It is only
+// available on Windows and fails elsewhere.
+func ToEventLog(source string) error {
+	return errors.New("assert: the Windows Event Log is only available on Windows")
+}
+
+func (l *eventLog) record(r *report) error {
+	return errors.ErrUnsupported
+}
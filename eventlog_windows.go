@@ -0,0 +1,96 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                 = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW = advapi32.NewProc("RegisterEventSourceW")
+	procReportEventW         = advapi32.NewProc("ReportEventW")
+)
+
+const (
+	eventlogErrorType = 0x0001
+	eventID           = 1
+	// maxEventStrings bounds the insertion strings of one event.
+	maxEventStrings = 256
+	// maxEventString is the longest insertion string the event log accepts.
+	maxEventString = 31839
+)
+
+type eventLog struct {
+	handle uintptr
+}
+
+// ToEventLog records fatal failures in the Windows Event Log under source,
+// in addition to writing the report to the configured output. Each event has
+// the error type and carries the failure summary, every key/value pair and
+// the stack as separate insertion strings, so they can be filtered and
+// forwarded individually. It is only available on Windows.
+func ToEventLog(source string) error {
+	name, err := syscall.UTF16PtrFromString(source)
+	if err != nil {
+		return err
+	}
+	h, _, err := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(name)))
+	if h == 0 {
+		return fmt.Errorf("assert: registering event source %q: %w", source, err)
+	}
+	l := &eventLog{handle: h}
+	updateConfig(func(c *config) { c.eventLog = l })
+	return nil
+}
+
+func (l *eventLog) record(r *report) error {
+	strs := []string{"assertion failed: " + r.summary()}
+	pairs := r.pairs()
+	for i := 0; i+1 < len(pairs) && len(strs) < maxEventStrings-1; i += 2 {
+		strs = append(strs, fmt.Sprintf("%v=%v", pairs[i], pairs[i+1]))
+	}
+	if r.stack != nil {
+		strs = append(strs, string(r.stack))
+	}
+
+	ptrs := make([]*uint16, 0, len(strs))
+	for _, s := range strs {
+		p, err := syscall.UTF16PtrFromString(eventString(s))
+		if err != nil {
+			return err
+		}
+		ptrs = append(ptrs, p)
+	}
+	ok, _, err := procReportEventW.Call(
+		l.handle,
+		eventlogErrorType,
+		0,
+		eventID,
+		0,
+		uintptr(len(ptrs)),
+		0,
+		uintptr(unsafe.Pointer(&ptrs[0])),
+		0,
+	)
+	if ok == 0 {
+		return err
+	}
+	return nil
+}
+
+// eventString makes s acceptable as an insertion string.
+func eventString(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c == 0 {
+			b[i] = ' '
+		}
+	}
+	if len(b) > maxEventString {
+		b = b[:maxEventString]
+	}
+	return string(b)
+}
@@ -0,0 +1,143 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"time"
+)
+
+// EventuallyOption changes how Eventually polls. Options are passed among
+// the assertion's data and are not reported.
+type EventuallyOption func(*eventually)
+
+type eventually struct {
+	interval    time.Duration
+	maxInterval time.Duration // 0 for a fixed interval
+	jitter      float64
+	maxAttempts int
+	onAttempt   func(attempt int) string
+}
+
+// Interval polls every d. The default is every 10ms. A non-positive d is
+// ignored, as it would poll without pause.
+func Interval(d time.Duration) EventuallyOption {
+	return func(e *eventually) {
+		if d > 0 {
+			e.interval = d
+			e.maxInterval = 0
+		}
+	}
+}
+
+// Backoff polls after initial, then doubles the wait after each attempt up
+// to max, for conditions that depend on slow or flaky external services. A
+// non-positive initial is ignored, and a max below initial is raised to it.
+func Backoff(initial, max time.Duration) EventuallyOption {
+	return func(e *eventually) {
+		if initial > 0 {
+			e.interval, e.maxInterval = initial, initial
+			if max > initial {
+				e.maxInterval = max
+			}
+		}
+	}
+}
+
+// Jitter randomises each wait by up to frac of it in either direction, so
+// many processes polling the same dependency do not do it in lockstep. frac
+// is limited to 1; zero, negative and NaN fractions turn jitter off.
+func Jitter(frac float64) EventuallyOption {
+	return func(e *eventually) {
+		e.jitter = 0
+		if frac > 0 {
+			e.jitter = min(frac, 1)
+		}
+	}
+}
+
+// MaxAttempts gives up after n evaluations of the condition, even before
+// the timeout. A non-positive n is ignored.
+func MaxAttempts(n int) EventuallyOption {
+	return func(e *eventually) { e.maxAttempts = n }
+}
+
+// OnAttempt calls fn after every attempt that finds the condition false and
+// records what it returns as a breadcrumb, so the report shows how the
+// dependency behaved while it was being waited on.
+func OnAttempt(fn func(attempt int) string) EventuallyOption {
+	return func(e *eventually) { e.onAttempt = fn }
+}
+
+// Eventually polls cond until it returns true and fails if it has not by
+// the time timeout has passed. It uses the simulation clock and random
+// source while a Simulation runs.
+//
+//	assert.Eventually(replica.CaughtUp, 30*time.Second, "replica never caught up",
+//		assert.Backoff(50*time.Millisecond, 2*time.Second), assert.Jitter(0.2),
+//		assert.OnAttempt(func(int) string { return replica.Status() }),
+//		"replica", replica.ID)
+func Eventually(cond func() bool, timeout time.Duration, msg string, data ...any) {
+	if Enabled() {
+		runEventually(cond, timeout, msg, data)
+	}
+}
+
+func runEventually(cond func() bool, timeout time.Duration, msg string, data []any) {
+	e := eventually{interval: 10 * time.Millisecond}
+	var rest []any
+	for i, v := range data {
+		opt, ok := v.(EventuallyOption)
+		if !ok {
+			if rest != nil {
+				rest = append(rest, v)
+			}
+			continue
+		}
+		if rest == nil {
+			rest = append(make([]any, 0, len(data)), data[:i]...)
+		}
+		if opt != nil {
+			opt(&e)
+		}
+	}
+	if rest != nil {
+		data = rest
+	}
+
+	start := now()
+	deadline := start.Add(timeout)
+	wait := e.interval
+	attempts := 0
+	for {
+		attempts++
+		if cond() {
+			if tracking.Load() {
+				passed(msg, data)
+			}
+			return
+		}
+		if e.onAttempt != nil {
+			Breadcrumb("eventually attempt", "attempt", attempts, "diagnostic", e.onAttempt(attempts))
+		}
+		if e.maxAttempts > 0 && attempts >= e.maxAttempts {
+			break
+		}
+		remaining := deadline.Sub(now())
+		if remaining <= 0 {
+			break
+		}
+		d := wait
+		if e.jitter > 0 {
+			d += time.Duration((2*randFloat64() - 1) * e.jitter * float64(d))
+		}
+		<-clock().After(min(max(d, 0), remaining))
+		if e.maxInterval > 0 {
+			wait = min(2*wait, e.maxInterval)
+		}
+	}
+	runAssert(msg, append(data,
+		"eventually.attempts", attempts,
+		"eventually.elapsed", now().Sub(start).Round(time.Millisecond).String(),
+		"eventually.timeout", timeout.String(),
+	)...)
+}
@@ -0,0 +1,51 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEventually(t *testing.T) {
+	tests := []struct {
+		name        string
+		succeeds    int // the attempt cond first returns true on, or 0 for never
+		opts        []any
+		fails       bool
+		attempts    int // exact attempts expected, or 0 to check only maxAttempts
+		maxAttempts int
+	}{
+		{"first attempt", 1, nil, false, 1, 0},
+		{"third attempt", 3, []any{Interval(time.Millisecond)}, false, 3, 0},
+		{"max attempts", 0, []any{Interval(time.Millisecond), MaxAttempts(4)}, true, 4, 0},
+		{"timeout", 0, nil, true, 0, 10},
+		// Non-positive durations would poll without pause until the timeout.
+		{"zero interval", 0, []any{Interval(0)}, true, 0, 10},
+		{"negative interval", 0, []any{Interval(-time.Second)}, true, 0, 10},
+		{"zero backoff", 0, []any{Backoff(0, 0)}, true, 0, 10},
+		{"backoff below initial", 0, []any{Backoff(20*time.Millisecond, 0)}, true, 0, 5},
+		{"full jitter", 0, []any{Jitter(math.Inf(1))}, true, 0, 20},
+		{"nan jitter", 0, []any{Jitter(math.NaN())}, true, 0, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := 0
+			cond := func() bool {
+				n++
+				return n == tt.succeeds
+			}
+			data := append([]any{"k", "v"}, tt.opts...)
+			if got := fails(t, func() { Eventually(cond, 50*time.Millisecond, "never", data...) }); got != tt.fails {
+				t.Errorf("failed = %v, want %v", got, tt.fails)
+			}
+			if tt.attempts > 0 && n != tt.attempts {
+				t.Errorf("%d attempts, want %d", n, tt.attempts)
+			}
+			if tt.maxAttempts > 0 && n > tt.maxAttempts {
+				t.Errorf("%d attempts, want at most %d", n, tt.maxAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"io"
+	"time"
+)
+
+// defaultExitFlushTimeout is how long fatal failures wait for their outputs
+// unless SetExitFlushTimeout says otherwise.
+const defaultExitFlushTimeout = 2 * time.Second
+
+// SyncOnExit controls whether fatal failures call Sync on the outputs their
+// reports are written to, such as os.Stderr or a log file, before exiting,
+// so a report written just before the process ends is not lost with the
+// machine. Outputs with a Flush method, such as a *bufio.Writer, are flushed
+// before exiting either way, and crash files are always synced. It is off by
+// default.
+func SyncOnExit(on bool) {
+	updateConfig(func(c *config) { c.syncOnExit = on })
+}
+
+// SetExitFlushTimeout bounds how long fatal failures wait for their crash
+// files, termination log and event log to be written and their outputs to
+// be flushed and synced before exiting, so a stuck disk or output cannot keep
+// a crashing process alive. OnFatal hooks run in between and are bounded by
+// their own timeout. The default is two seconds.
+func SetExitFlushTimeout(d time.Duration) {
+	updateConfig(func(c *config) { c.flushTimeout = d })
+}
+
+// exitWithReport leaves the records of the fatal failure r, runs the OnFatal
+// hooks, flushes the outputs and exits. Everything but the hooks shares one
+// deadline, the exit flush timeout.
+func exitWithReport(c *config, r *report) {
+	deadline := time.Now().Add(c.flushTimeout)
+	until(deadline, func() {
+		writeCrashFiles(c, r)
+		writeTerminationLog(c, r)
+		writeEventLog(c, r)
+	})
+	runFatalHooks(c)
+	until(deadline, func() { flushOutputs(c) })
+	c.exit(1)
+}
+
+// until runs fn, waiting for it until deadline at most.
+func until(deadline time.Time, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	t := time.NewTimer(time.Until(deadline))
+	defer t.Stop()
+	select {
+	case <-done:
+	case <-t.C:
+	}
+}
+
+// flushOutputs flushes, and with SyncOnExit syncs, the output and routed
+// outputs.
+func flushOutputs(c *config) {
+	outputs := []io.Writer{c.output()}
+	for _, w := range c.routes {
+		outputs = append(outputs, w)
+	}
+	for _, w := range outputs {
+		switch f := w.(type) {
+		case interface{ Flush() error }:
+			f.Flush()
+		case interface{ Flush() }:
+			f.Flush()
+		}
+		if s, ok := w.(interface{ Sync() error }); ok && c.syncOnExit {
+			s.Sync()
+		}
+	}
+}
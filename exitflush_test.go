@@ -0,0 +1,30 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"testing"
+	"time"
+)
+
+// stuckWriter discards writes and never returns from Flush.
+type stuckWriter struct{}
+
+func (stuckWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (stuckWriter) Flush()                      { select {} }
+
+func TestExitFlushTimeout(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	exited := make(chan int, 1)
+	SetExitFunc(func(code int) { exited <- code })
+	SetExitFlushTimeout(20 * time.Millisecond)
+	SetMode(ModeExit)
+	ToWriter(stuckWriter{})
+
+	go Assert(false, "fails")
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a stuck output kept the process from exiting")
+	}
+}
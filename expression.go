@@ -0,0 +1,65 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type exprKey struct {
+	pkg  string
+	file string
+	line int
+}
+
+var exprMu sync.RWMutex
+var expressions = map[exprKey]string{}
+
+// RegisterExpressions records the source text of the conditions checked by
+// the assertions in package pkg, keyed by "file.go:line". Failures at those
+// sites then include it as "expression", like C's assert. Entries whose key
+// is not of that form are ignored. It is called from code generated by
+// cmd/assertexpr rather than by hand:
+//
+//	//go:generate go run github.com/bhuvneshuchiha/assert/cmd/assertexpr
+func RegisterExpressions(pkg string, exprs map[string]string) {
+	exprMu.Lock()
+	defer exprMu.Unlock()
+
+	for site, expr := range exprs {
+		i := strings.LastIndexByte(site, ':')
+		if i < 0 {
+			continue
+		}
+		line, err := strconv.Atoi(site[i+1:])
+		if err != nil || line <= 0 {
+			continue
+		}
+		expressions[exprKey{pkg: pkg, file: site[:i], line: line}] = expr
+	}
+}
+
+// expression returns the registered condition text for the call in frame.
+func expression(frame runtime.Frame) string {
+	exprMu.RLock()
+	defer exprMu.RUnlock()
+
+	if len(expressions) == 0 {
+		return ""
+	}
+	return expressions[exprKey{pkg: funcPackage(frame.Function), file: filepath.Base(frame.File), line: frame.Line}]
+}
+
+// funcPackage returns the import path of the package a function, as named
+// by runtime.Frame.Function, belongs to.
+func funcPackage(fn string) string {
+	slash := strings.LastIndexByte(fn, '/')
+	if i := strings.IndexByte(fn[slash+1:], '.'); i >= 0 {
+		return fn[:slash+1+i]
+	}
+	return fn
+}
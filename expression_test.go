@@ -0,0 +1,30 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestRegisterExpressions(t *testing.T) {
+	const pkg = "example.com/exprtest"
+	RegisterExpressions(pkg, map[string]string{
+		"a.go:12":  "x > 0",
+		"a.go:1x3": "bad line",
+		"a.go:-4":  "negative line",
+		"a.go:":    "no line",
+		"a.go":     "no colon",
+	})
+	frame := func(line int) runtime.Frame {
+		return runtime.Frame{Function: pkg + ".f", File: "/src/a.go", Line: line}
+	}
+	if got := expression(frame(12)); got != "x > 0" {
+		t.Errorf("a.go:12 = %q, want x > 0", got)
+	}
+	for _, line := range []int{13, 103, -4, 0} {
+		if got := expression(frame(line)); got != "" {
+			t.Errorf("a.go:%d = %q, want nothing", line, got)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailureDiff is a difference between two failures found by DiffFailures.
+// A and B are the formatted values in each; A is empty for ChangeAdded
+// fields and B for ChangeRemoved ones.
+type FailureDiff struct {
+	Field string // "message", "severity", "site", "data.<key>", "stack", ...
+	Kind  ChangeKind
+	A     string
+	B     string
+}
+
+// DiffFailures compares two failures, typically the same invariant tripping
+// on two nodes, and returns how they differ: their metadata, each data key,
+// their breadcrumbs and logs, and their stacks. Values of a key that appears
+// more than once are compared together. Stacks are compared by their frames,
+// ignoring goroutine IDs, arguments and program counter offsets. Times,
+// suppressed counts and process details, which differ between any two
+// failures, are not compared:
+//
+//	for _, d := range assert.DiffFailures(a, b) {
+//		fmt.Printf("%s: %q vs %q\n", d.Field, d.A, d.B)
+//	}
+func DiffFailures(a, b Failure) []FailureDiff {
+	var diffs []FailureDiff
+	field := func(name, x, y string) {
+		switch {
+		case x == y:
+		case x == "":
+			diffs = append(diffs, FailureDiff{name, ChangeAdded, x, y})
+		case y == "":
+			diffs = append(diffs, FailureDiff{name, ChangeRemoved, x, y})
+		default:
+			diffs = append(diffs, FailureDiff{name, ChangeModified, x, y})
+		}
+	}
+	field("message", a.Message, b.Message)
+	field("area", a.Area, b.Area)
+	field("severity", a.Severity.String(), b.Severity.String())
+	field("id", a.ID, b.ID)
+	field("mode", a.Mode.String(), b.Mode.String())
+	field("site", fmt.Sprintf("%s:%d", a.File, a.Line), fmt.Sprintf("%s:%d", b.File, b.Line))
+	field("function", a.Function, b.Function)
+	field("expression", a.Expression, b.Expression)
+	field("fingerprint", a.Fingerprint, b.Fingerprint)
+
+	keys, av := dataValues(a.Data)
+	bkeys, bv := dataValues(b.Data)
+	for _, k := range bkeys {
+		if _, ok := av[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		field("data."+k, av[k], bv[k])
+	}
+
+	field("breadcrumbs", eventsText(a.Breadcrumbs), eventsText(b.Breadcrumbs))
+	field("logs", eventsText(a.Logs), eventsText(b.Logs))
+	field("stack", StackFrames(a.Stack), StackFrames(b.Stack))
+	return diffs
+}
+
+// dataValues returns the keys of data in order of first appearance and the
+// formatted values of each, joined by newlines when a key repeats.
+func dataValues(data []any) ([]string, map[string]string) {
+	var keys []string
+	values := map[string]string{}
+	for _, a := range attrs(data) {
+		v := fmt.Sprint(a.Value)
+		if prev, ok := values[a.Key]; ok {
+			values[a.Key] = prev + "\n" + v
+			continue
+		}
+		keys = append(keys, a.Key)
+		values[a.Key] = v
+	}
+	return keys, values
+}
+
+// eventsText formats events one per line, without their times.
+func eventsText(events []Event) string {
+	var b strings.Builder
+	for _, e := range events {
+		_, s, _ := strings.Cut(e.String(), " ")
+		b.WriteString(s)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// StackFrames reduces a stack trace as captured in failure reports to its
+// frames, one "function file:line" line each, leaving out goroutine headers,
+// arguments and program counter offsets so that stacks of the same code path
+// compare equal across processes.
+func StackFrames(stack string) string {
+	var b strings.Builder
+	lines := strings.Split(stack, "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		fn, loc := lines[i], lines[i+1]
+		if strings.HasPrefix(fn, "\t") || !strings.HasPrefix(loc, "\t") {
+			continue
+		}
+		i++
+		if j := strings.Index(fn, " in goroutine "); strings.HasPrefix(fn, "created by ") && j > 0 {
+			fn = fn[:j]
+		} else if strings.HasSuffix(fn, ")") {
+			if j := strings.LastIndexByte(fn, '('); j > 0 {
+				fn = fn[:j]
+			}
+		}
+		loc = strings.TrimSpace(loc)
+		if j := strings.LastIndex(loc, " +0x"); j >= 0 {
+			loc = loc[:j]
+		}
+		b.WriteString(fn)
+		b.WriteByte(' ')
+		b.WriteString(loc)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,255 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Failure is the structured form of a failed assertion. Observers
+// registered with OnFailure receive it, AssertionError.Failure returns it
+// for failures that panicked or were returned by the Check functions, and
+// FormatJSON writes it, one object per line, as FormatBinary does in binary.
+//
+// Data holds key/value pairs with string keys: those passed to the
+// assertion, then, once the report has been collected, the failing
+// goroutine's pprof labels, the dumps of the registered AssertData, what
+// changed since the last Snapshot and the process context. A value passed
+// without a key is kept under the key "!BADKEY", as slog does. JSON writes
+// the pairs as a list of {"key", "value"} objects.
+type Failure struct {
+	Message     string    `json:"message"`
+	Area        string    `json:"area"`
+	Severity    Severity  `json:"severity"`
+	Mode        Mode      `json:"mode"` // how the failure is handled; ModeWarn for Check errors
+	File        string    `json:"file"`
+	Line        int       `json:"line"`
+	Time        time.Time `json:"time"`
+	Data        []any     `json:"data,omitempty"`
+	Fingerprint string    `json:"fingerprint"`
+
+	ID          string   `json:"id,omitempty"`         // see IDKey
+	Function    string   `json:"function"`             // function containing the assertion
+	Expression  string   `json:"expression,omitempty"` // see RegisterExpressions
+	Stack       string   `json:"stack,omitempty"`
+	Suppressed  uint64   `json:"suppressed,omitempty"` // failures dropped by the rate limit before this one
+	Breadcrumbs []Event  `json:"breadcrumbs,omitempty"`
+	Logs        []Event  `json:"logs,omitempty"`     // see TapSlog
+	SimSeed     *uint64  `json:"sim_seed,omitempty"` // seed of the running Simulation
+	Process     *Process `json:"process,omitempty"`  // set in post-mortem files
+}
+
+// Event is a breadcrumb or log record included in a Failure. Level is empty
+// for breadcrumbs, and Data holds key/value pairs as in Failure.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level,omitempty"`
+	Message string    `json:"message"`
+	Data    []any     `json:"data,omitempty"`
+}
+
+func (e Event) String() string {
+	var s strings.Builder
+	s.WriteString(e.Time.Format("15:04:05.000000"))
+	if e.Level != "" {
+		s.WriteByte(' ')
+		s.WriteString(e.Level)
+	}
+	s.WriteByte(' ')
+	s.WriteString(e.Message)
+	for i := 0; i+1 < len(e.Data); i += 2 {
+		fmt.Fprintf(&s, " %s=%v", e.Data[i], e.Data[i+1])
+	}
+	return s.String()
+}
+
+// Process describes the process a failure happened in.
+type Process struct {
+	PID       int      `json:"pid"`
+	Args      []string `json:"args"`
+	Hostname  string   `json:"hostname,omitempty"`
+	GoVersion string   `json:"go_version"`
+	GOOS      string   `json:"goos"`
+	GOARCH    string   `json:"goarch"`
+}
+
+// attr is the JSON form of a key/value pair of a Failure's data.
+type attr struct {
+	Key   string
+	Value any
+}
+
+// MarshalJSON encodes a as {"key": ..., "value": ...}. Errors are written
+// as their message, and values that cannot be encoded as JSON as they are
+// formatted in text reports.
+func (a attr) MarshalJSON() ([]byte, error) {
+	value := a.Value
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		if v, err = json.Marshal(jsonValue(value)); err != nil {
+			v, _ = json.Marshal(fmt.Sprint(value))
+		}
+	}
+	k, _ := json.Marshal(a.Key)
+	return fmt.Appendf(nil, `{"key":%s,"value":%s}`, k, v), nil
+}
+
+// attrs returns the key/value pairs of data, as normalized by pairs.
+func attrs(data []any) []attr {
+	out := make([]attr, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		out = append(out, attr{Key: fmt.Sprint(data[i]), Value: data[i+1]})
+	}
+	return out
+}
+
+// fromAttrs returns the key/value pairs of attrs.
+func fromAttrs(attrs []attr) []any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]any, 0, 2*len(attrs))
+	for _, a := range attrs {
+		out = append(out, a.Key, a.Value)
+	}
+	return out
+}
+
+// jsonFailure is the JSON form of a Failure, and of a post-mortem file when
+// Version is set.
+type jsonFailure struct {
+	Version int `json:"version,omitempty"`
+	*plainFailure
+	Data []attr `json:"data,omitempty"`
+}
+
+// plainFailure is Failure without its JSON methods.
+type plainFailure Failure
+
+// MarshalJSON encodes f with its data as a list of {"key", "value"}
+// objects.
+func (f Failure) MarshalJSON() ([]byte, error) {
+	return f.marshalJSON(0)
+}
+
+// marshalJSON encodes f, with the post-mortem version if it is not 0.
+func (f Failure) marshalJSON(version int) ([]byte, error) {
+	return json.Marshal(jsonFailure{Version: version, plainFailure: (*plainFailure)(&f), Data: attrs(f.Data)})
+}
+
+// UnmarshalJSON decodes a Failure encoded by MarshalJSON.
+func (f *Failure) UnmarshalJSON(b []byte) error {
+	_, err := f.unmarshalJSON(b)
+	return err
+}
+
+// unmarshalJSON decodes b into f and returns the post-mortem version it
+// carries, or 0.
+func (f *Failure) unmarshalJSON(b []byte) (version int, err error) {
+	var out Failure
+	j := jsonFailure{plainFailure: (*plainFailure)(&out)}
+	if err := json.Unmarshal(b, &j); err != nil {
+		return 0, err
+	}
+	out.Data = fromAttrs(j.Data)
+	*f = out
+	return j.Version, nil
+}
+
+// plainEvent is Event without its JSON methods.
+type plainEvent Event
+
+// MarshalJSON encodes e with its data as Failure.MarshalJSON does.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*plainEvent
+		Data []attr `json:"data,omitempty"`
+	}{(*plainEvent)(&e), attrs(e.Data)})
+}
+
+// UnmarshalJSON decodes an Event encoded by MarshalJSON.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var out Event
+	j := struct {
+		*plainEvent
+		Data []attr `json:"data,omitempty"`
+	}{plainEvent: (*plainEvent)(&out)}
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	out.Data = fromAttrs(j.Data)
+	*e = out
+	return nil
+}
+
+// Failure returns the structured form of the failure.
+func (e *AssertionError) Failure() Failure {
+	return e.r.failure()
+}
+
+func (r *report) failure() Failure {
+	f := Failure{
+		Message:     r.msg,
+		Area:        r.area,
+		Severity:    r.severity,
+		Mode:        r.mode,
+		File:        r.site.file,
+		Line:        r.site.line,
+		Time:        r.time,
+		Data:        pairs(r.args, r.labels, r.dumps, r.changes, r.system),
+		Fingerprint: r.fingerprint,
+		ID:          r.id,
+		Function:    r.function,
+		Expression:  r.expr,
+		Stack:       string(r.stack),
+		Suppressed:  r.dropped,
+	}
+	for _, c := range r.crumbs {
+		f.Breadcrumbs = append(f.Breadcrumbs, Event{Time: c.time, Message: c.msg, Data: pairs(c.data)})
+	}
+	for _, l := range r.logs {
+		f.Logs = append(f.Logs, Event{Time: l.time, Level: l.level.String(), Message: l.msg, Data: pairs(l.data)})
+	}
+	if r.simulated {
+		seed := r.simSeed
+		f.SimSeed = &seed
+	}
+	return f
+}
+
+// badKey is the key of a value passed without one, as in slog.
+const badKey = "!BADKEY"
+
+// pairs joins key/value pair lists, formatting keys as strings and keeping a
+// trailing value that has no key under badKey.
+func pairs(lists ...[]any) []any {
+	n := 0
+	for _, l := range lists {
+		n += len(l)
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make([]any, 0, n+len(lists))
+	for _, l := range lists {
+		for i := 0; i < len(l); i += 2 {
+			if i+1 == len(l) {
+				out = append(out, badKey, l[i])
+				break
+			}
+			key, ok := l[i].(string)
+			if !ok {
+				key = fmt.Sprint(l[i])
+			}
+			out = append(out, key, l[i+1])
+		}
+	}
+	return slices.Clip(out)
+}
@@ -0,0 +1,123 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type celsius float64
+
+// testReport returns a report with every field the encoders write set.
+func testReport(args ...any) *report {
+	seed := uint64(42)
+	return &report{
+		msg:         "queue depth exceeded",
+		area:        "queue",
+		severity:    SeverityError,
+		id:          "queue-depth",
+		time:        time.Date(2024, 1, 2, 15, 4, 5, 6000, time.UTC),
+		site:        siteKey{file: "queue.go", line: 17},
+		function:    "example.com/queue.(*Q).Push",
+		mode:        ModeWarn,
+		fingerprint: "9f86d081884c7d65",
+		expr:        "len(q) < max",
+		args:        args,
+		crumbs:      []breadcrumb{{time: time.Date(2024, 1, 2, 15, 4, 4, 0, time.UTC), msg: "push", data: []any{"n", 3}}},
+		stack:       []byte("goroutine 1 [running]:\n"),
+		dropped:     2,
+		simSeed:     seed,
+		simulated:   true,
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want any // as decoded into an any
+	}{
+		{"int", 3, 3.0},
+		{"string", "s", "s"},
+		{"bool", false, false},
+		{"nil", nil, nil},
+		{"error", errors.New("boom"), "boom"},
+		{"nan", math.NaN(), "NaN"},
+		{"inf", math.Inf(-1), "-Inf"},
+		{"float32 nan", float32(math.NaN()), "NaN"},
+		{"duration", time.Second, 1e9},
+		{"named", celsius(1.5), 1.5},
+		{"channel", make(chan int), nil}, // formatted as an address; checked below
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			testReport("v", tt.in).renderJSON(&b)
+			if n := bytes.Count(b.Bytes(), []byte("\n")); n != 1 {
+				t.Fatalf("%d lines, want 1:\n%s", n, b.Bytes())
+			}
+			var got Failure
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatalf("%v:\n%s", err, b.Bytes())
+			}
+			if got.Message != "queue depth exceeded" || got.Severity != SeverityError || got.Mode != ModeWarn ||
+				got.ID != "queue-depth" || got.Line != 17 || *got.SimSeed != 42 || len(got.Breadcrumbs) != 1 {
+				t.Errorf("got %+v", got)
+			}
+			v := got.Data[1]
+			if _, ok := tt.in.(chan int); ok {
+				if s, ok := v.(string); !ok || s == "" {
+					t.Errorf("value = %#v, want the formatted channel", v)
+				}
+				return
+			}
+			if !reflect.DeepEqual(v, tt.want) {
+				t.Errorf("value = %#v, want %#v", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureBadKey(t *testing.T) {
+	f := testReport("n", 1, "orphan").failure()
+	want := []any{"n", 1, badKey, "orphan"}
+	if !reflect.DeepEqual(f.Data, want) {
+		t.Fatalf("data = %#v, want %#v", f.Data, want)
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Failure
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Data) != 4 || got.Data[2] != badKey || got.Data[3] != "orphan" {
+		t.Errorf("data after JSON = %#v, want the orphan under %s", got.Data, badKey)
+	}
+}
+
+func TestObserverStack(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	defer observers.Store(observers.Load())
+	defer ReportOnce(reportOnce.Load())
+	SetMode(ModeWarn)
+	ToWriter(io.Discard)
+	ReportOnce(true)
+
+	var stacks []bool
+	OnFailure(func(f Failure) { stacks = append(stacks, f.Stack != "") })
+	for range 2 {
+		Assert(false, "repeated")
+	}
+	if want := []bool{true, false}; !reflect.DeepEqual(stacks, want) {
+		t.Errorf("stack captured = %v, want %v: only the delivered failure", stacks, want)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// variablePart matches the parts of a message that vary between otherwise
+// identical failures: numbers, including hexadecimal ones.
+var variablePart = regexp.MustCompile(`0[xX][0-9a-fA-F]+|[0-9]+`)
+
+// fingerprint identifies a failure by its call site and normalized message.
+// The site is the qualified function name and line, which unlike file paths
+// does not depend on where the binary was built, so identical failures get
+// the same fingerprint across a fleet.
+func fingerprint(frame runtime.Frame, msg string) string {
+	site := frame.Function
+	if site == "" {
+		site = filepath.Base(frame.File)
+	}
+	msg = variablePart.ReplaceAllString(msg, "#")
+	msg = strings.Join(strings.Fields(strings.ToLower(msg)), " ")
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s:%d\x00%s", site, frame.Line, msg)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// setSite records where r failed.
+func (r *report) setSite(frame runtime.Frame) {
+	r.site = siteKey{frame.File, frame.Line}
+	r.function = frame.Function
+	r.fingerprint = fingerprint(frame, r.msg)
+	r.expr = expression(frame)
+}
+
+// Fingerprint returns a stable identifier of the failure derived from its
+// call site and message, with numbers ignored. Failures of the same
+// assertion share it across processes and hosts, so it can be used to group
+// crashes and to reference failures in suppression lists.
+func (e *AssertionError) Fingerprint() string {
+	return e.r.fingerprint
+}
@@ -0,0 +1,95 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// HasFlags asserts that every bit set in flags is also set in value.
+//
+//	assert.HasFlags(desc.Status, StatusOwned|StatusValid, "descriptor not ready", "ring", i)
+func HasFlags[T Integer](value, flags T, msg string, data ...any) {
+	failed := value&flags != flags
+	if failed || tracking.Load() {
+		flagsChecked(failed, value, flags, flags&^value, "mask", "missing", msg, data)
+	}
+}
+
+// NoFlags asserts that none of the bits set in flags is set in value.
+func NoFlags[T Integer](value, flags T, msg string, data ...any) {
+	failed := value&flags != 0
+	if failed || tracking.Load() {
+		flagsChecked(failed, value, flags, value&flags, "mask", "unexpected", msg, data)
+	}
+}
+
+// ExactlyFlags asserts that the bits set in value are exactly those set in
+// want.
+func ExactlyFlags[T Integer](value, want T, msg string, data ...any) {
+	failed := value != want
+	if failed || tracking.Load() {
+		flagsChecked(failed, value, want, value^want, "expected", "differing", msg, data)
+	}
+}
+
+// flagsChecked reports value against flags, named flagsName, with the bits
+// that broke the assertion, diff, named diffName.
+func flagsChecked[T Integer](failed bool, value, flags, diff T, flagsName, diffName, msg string, data []any) {
+	if !failed {
+		passed(msg, data)
+		return
+	}
+	bits := int(unsafe.Sizeof(value)) * 8
+	runAssert(msg, append(data,
+		"flags.value", formatFlags(value, bits),
+		"flags."+flagsName, formatFlags(flags, bits),
+		"flags."+diffName, formatFlags(diff, bits),
+		"flags.bits", flagBits(value, flags, diff, flagsName, bits),
+	)...)
+}
+
+// formatFlags renders v in hex, as the unsigned value of its width.
+func formatFlags[T Integer](v T, bits int) string {
+	return fmt.Sprintf("%#0*x", bits/4, maskBits(v, bits))
+}
+
+// flagBits renders value and flags in binary with the bits set in diff
+// marked below them, most significant bit first and in groups of four.
+func flagBits[T Integer](value, flags, diff T, flagsName string, bits int) string {
+	var b strings.Builder
+	line := func(label string, v uint64, mark bool) {
+		b.WriteString("\n  ")
+		fmt.Fprintf(&b, "%-9s", label)
+		for i := bits - 1; i >= 0; i-- {
+			set := v>>uint(i)&1 == 1
+			switch {
+			case mark && set:
+				b.WriteByte('^')
+			case mark:
+				b.WriteByte(' ')
+			case set:
+				b.WriteByte('1')
+			default:
+				b.WriteByte('0')
+			}
+			if i > 0 && i%4 == 0 {
+				b.WriteByte(' ')
+			}
+		}
+	}
+	line("value", maskBits(value, bits), false)
+	line(flagsName, maskBits(flags, bits), false)
+	line("", maskBits(diff, bits), true)
+	return strings.TrimRight(b.String(), " ")
+}
+
+func maskBits[T Integer](v T, bits int) uint64 {
+	u := uint64(v)
+	if bits < 64 {
+		u &= 1<<uint(bits) - 1
+	}
+	return u
+}
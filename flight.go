@@ -0,0 +1,67 @@
+//go:build go1.25 && !tinygo
+
+package assert
+
+import (
+	"io"
+	"runtime/trace"
+	"sync"
+	"time"
+)
+
+var flightMu sync.Mutex
+var flightRecorder *trace.FlightRecorder
+
+// EnableFlightRecorder starts the runtime's execution trace flight recorder,
+// keeping roughly the last window of execution. Fatal failures then write
+// the recording next to their report in the crash directory set with
+// SetCrashDir, as a ".trace" file for "go tool trace". It gives the
+// scheduling and blocking context that stacks alone cannot. It requires
+// Go 1.25; at most one flight recorder can be active in a process.
+func EnableFlightRecorder(window time.Duration) error {
+	flightMu.Lock()
+	defer flightMu.Unlock()
+
+	if flightRecorder != nil {
+		flightRecorder.Stop()
+		flightRecorder = nil
+	}
+	fr := trace.NewFlightRecorder(trace.FlightRecorderConfig{MinAge: window})
+	if err := fr.Start(); err != nil {
+		return err
+	}
+	flightRecorder = fr
+	return nil
+}
+
+// DisableFlightRecorder stops the flight recorder started by
+// EnableFlightRecorder.
+func DisableFlightRecorder() {
+	flightMu.Lock()
+	defer flightMu.Unlock()
+
+	if flightRecorder != nil {
+		flightRecorder.Stop()
+		flightRecorder = nil
+	}
+}
+
+// writeFlightRecording writes the flight recorder's window to the file at
+// path made by create, if the recorder is running.
+func writeFlightRecording(path string, create func(string) (io.WriteCloser, error)) error {
+	flightMu.Lock()
+	defer flightMu.Unlock()
+
+	if flightRecorder == nil {
+		return nil
+	}
+	f, err := create(path)
+	if err != nil {
+		return err
+	}
+	_, err = flightRecorder.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
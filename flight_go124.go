@@ -0,0 +1,22 @@
+//go:build !go1.25 && !tinygo
+
+package assert
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// EnableFlightRecorder requires Go 1.25 and fails on older releases.
+func EnableFlightRecorder(window time.Duration) error {
+	return errors.New("assert: the flight recorder requires Go 1.25")
+}
+
+// DisableFlightRecorder stops the flight recorder started by
+// EnableFlightRecorder.
+func DisableFlightRecorder() {}
+
+func writeFlightRecording(path string, create func(string) (io.WriteCloser, error)) error {
+	return nil
+}
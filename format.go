@@ -0,0 +1,98 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Format selects how reports are rendered on the configured output.
+type Format int
+
+const (
+	// FormatText is the multi-line human readable report. This is the
+	// default.
+	FormatText Format = iota
+	// FormatGCP is single-line JSON in the Google Cloud structured logging
+	// schema, recognised by Error Reporting. See SetServiceContext.
+	FormatGCP
+	// FormatJSON is the Failure type encoded as single-line JSON.
+	FormatJSON
+	// FormatBinary is the Failure type in the encoding of
+	// Failure.MarshalBinary, each report a length prefixed record that
+	// FailureDecoder reads back.
+	FormatBinary
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatGCP:
+		return "gcp"
+	case FormatJSON:
+		return "json"
+	case FormatBinary:
+		return "binary"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// MarshalText encodes f as its name, so that it reads well in JSON.
+func (f Format) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText decodes a name as returned by Format.String.
+func (f *Format) UnmarshalText(b []byte) error {
+	v, err := ParseFormat(string(b))
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// ParseFormat parses the name of a format as returned by Format.String.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text":
+		return FormatText, nil
+	case "gcp":
+		return FormatGCP, nil
+	case "json":
+		return FormatJSON, nil
+	case "binary":
+		return FormatBinary, nil
+	}
+	return FormatText, fmt.Errorf("assert: unknown format %q", s)
+}
+
+// SetFormat sets how reports are rendered.
+func SetFormat(f Format) {
+	updateConfig(func(c *config) { c.format = f })
+}
+
+func (r *report) renderFormat(b *bytes.Buffer, f Format) {
+	switch f {
+	case FormatGCP:
+		r.renderGCP(b)
+	case FormatJSON:
+		r.renderJSON(b)
+	case FormatBinary:
+		r.renderBinary(b)
+	default:
+		r.render(b)
+	}
+}
+
+func (r *report) renderJSON(b *bytes.Buffer) {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r.failure()); err != nil {
+		fmt.Fprintf(b, "{\"message\":%q,\"error\":%q}\n", r.msg, err.Error())
+	}
+}
@@ -0,0 +1,153 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+type serviceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version,omitempty"`
+}
+
+var gcpService atomic.Pointer[serviceContext]
+
+// SetServiceContext sets the service name and version FormatGCP reports
+// failures under, which Error Reporting groups them by. By default they are
+// taken from K_SERVICE and K_REVISION, as set by Cloud Run and Knative, or
+// the program name.
+func SetServiceContext(service, version string) {
+	gcpService.Store(&serviceContext{Service: service, Version: version})
+}
+
+func defaultServiceContext() *serviceContext {
+	if sc := gcpService.Load(); sc != nil {
+		return sc
+	}
+	service := os.Getenv("K_SERVICE")
+	if service == "" {
+		service = filepath.Base(os.Args[0])
+	}
+	return &serviceContext{Service: service, Version: os.Getenv("K_REVISION")}
+}
+
+type gcpLocation struct {
+	FilePath   string `json:"filePath"`
+	LineNumber int    `json:"lineNumber"`
+}
+
+type gcpEntry struct {
+	Severity       string          `json:"severity"`
+	Message        string          `json:"message"`
+	Time           string          `json:"time"`
+	Type           string          `json:"@type"`
+	ServiceContext *serviceContext `json:"serviceContext"`
+	Context        *struct {
+		ReportLocation gcpLocation `json:"reportLocation"`
+	} `json:"context,omitempty"`
+	Area        string         `json:"area"`
+	Data        map[string]any `json:"data,omitempty"`
+	Breadcrumbs []string       `json:"breadcrumbs,omitempty"`
+	Logs        []string       `json:"logs,omitempty"`
+	Suppressed  uint64         `json:"suppressed,omitempty"`
+	SimSeed     *uint64        `json:"sim.seed,omitempty"`
+	Fingerprint string         `json:"fingerprint,omitempty"`
+}
+
+// gcpSeverities maps severities to Cloud Logging's LogSeverity names.
+var gcpSeverities = map[Severity]string{
+	SeverityDebug: "DEBUG",
+	SeverityWarn:  "WARNING",
+	SeverityError: "ERROR",
+	SeverityFatal: "CRITICAL",
+}
+
+// renderGCP writes r's Failure as one line of JSON. The stack is part of the
+// message, in the format Error Reporting parses, and the @type marks the
+// entry as an error event even when stacks are disabled.
+func (r *report) renderGCP(b *bytes.Buffer) {
+	f := r.failure()
+	e := gcpEntry{
+		Severity:       gcpSeverities[f.Severity],
+		Message:        "assertion failed: " + r.summary(),
+		Time:           f.Time.Format(time.RFC3339Nano),
+		Type:           "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent",
+		ServiceContext: defaultServiceContext(),
+		Area:           f.Area,
+		Suppressed:     f.Suppressed,
+		SimSeed:        f.SimSeed,
+		Fingerprint:    f.Fingerprint,
+	}
+	if f.Stack != "" {
+		e.Message += "\n\n" + f.Stack
+	}
+	if f.File != "" {
+		e.Context = &struct {
+			ReportLocation gcpLocation `json:"reportLocation"`
+		}{gcpLocation{FilePath: f.File, LineNumber: f.Line}}
+	}
+	if len(f.Data) > 0 {
+		e.Data = make(map[string]any, len(f.Data)/2)
+	}
+	for _, a := range attrs(f.Data) {
+		e.Data[a.Key] = jsonValue(a.Value)
+	}
+	for _, c := range f.Breadcrumbs {
+		e.Breadcrumbs = append(e.Breadcrumbs, c.String())
+	}
+	for _, l := range f.Logs {
+		e.Logs = append(e.Logs, l.String())
+	}
+
+	// The encoder escapes newlines, so the entry stays on one line.
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	mark := b.Len()
+	if err := enc.Encode(e); err != nil {
+		// Never lose the report to one bad value: retry with every data
+		// value as text.
+		b.Truncate(mark)
+		for k, v := range e.Data {
+			e.Data[k] = fmt.Sprint(v)
+		}
+		if err := enc.Encode(e); err != nil {
+			b.Truncate(mark)
+			fmt.Fprintf(b, "{\"severity\":\"ERROR\",\"message\":%q}\n", e.Message+"\n\n"+err.Error())
+		}
+	}
+}
+
+// jsonValue keeps values JSON represents natively and formats the rest
+// like the text report does. NaN and infinities, which JSON cannot
+// represent, are formatted as text.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return v
+	case float32:
+		if f := float64(v); math.IsNaN(f) || math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'g', -1, 32)
+		}
+		return v
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprintf("%v", v)
+}
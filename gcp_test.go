@@ -0,0 +1,56 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGCP(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want any
+	}{
+		{"int", 3, 3.0},
+		{"string", "s", "s"},
+		{"error", errors.New("boom"), "boom"},
+		{"nan", math.NaN(), "NaN"},
+		{"inf", math.Inf(1), "+Inf"},
+		{"named", celsius(1.5), "1.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			testReport("v", tt.in).renderGCP(&b)
+			var got struct {
+				Severity    string         `json:"severity"`
+				Message     string         `json:"message"`
+				Data        map[string]any `json:"data"`
+				Breadcrumbs []string       `json:"breadcrumbs"`
+				SimSeed     uint64         `json:"sim.seed"`
+				Context     struct {
+					ReportLocation gcpLocation `json:"reportLocation"`
+				} `json:"context"`
+			}
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatalf("%v:\n%s", err, b.Bytes())
+			}
+			if got.Severity != "ERROR" || got.SimSeed != 42 || len(got.Breadcrumbs) != 1 ||
+				got.Context.ReportLocation != (gcpLocation{"queue.go", 17}) {
+				t.Errorf("got %+v", got)
+			}
+			if !bytes.Contains([]byte(got.Message), []byte("goroutine 1 [running]")) {
+				t.Errorf("message %q lacks the stack", got.Message)
+			}
+			if v := got.Data["v"]; !reflect.DeepEqual(v, tt.want) {
+				t.Errorf("value = %#v, want %#v", v, tt.want)
+			}
+		})
+	}
+}
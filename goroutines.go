@@ -0,0 +1,118 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoroutineSnapshot records the goroutines running at some point, so that a
+// later AssertNoNew can find the ones started since and never stopped.
+//
+//	snap := assert.Goroutines()
+//	srv.Shutdown(ctx)
+//	snap.AssertNoNew("server leaked goroutines")
+type GoroutineSnapshot struct {
+	ids    map[uint64]bool
+	ignore []string
+}
+
+// Goroutines snapshots the goroutines running now.
+func Goroutines() *GoroutineSnapshot {
+	s := &GoroutineSnapshot{ids: map[uint64]bool{}}
+	for _, g := range parseGoroutines(allStacks()) {
+		s.ids[g.id] = true
+	}
+	return s
+}
+
+// Ignore excludes goroutines with any of the named functions on their stack,
+// such as long-lived pool workers, from AssertNoNew. Names are matched as
+// printed in stacks, e.g. "net/http.(*persistConn).readLoop", and a name
+// ending in "." matches a whole package. It returns s.
+func (s *GoroutineSnapshot) Ignore(funcs ...string) *GoroutineSnapshot {
+	s.ignore = append(s.ignore, funcs...)
+	return s
+}
+
+// AssertNoNew asserts that every goroutine running now, other than the
+// caller and the ignored ones, was already running when s was taken. As
+// goroutines that were just told to stop may not have exited yet, it checks
+// a few times over about 50ms before failing. The report includes the
+// stacks of the leaked goroutines.
+func (s *GoroutineSnapshot) AssertNoNew(msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	var leaked []goroutine
+	for wait := time.Millisecond; ; wait *= 2 {
+		leaked = s.leaked()
+		if len(leaked) == 0 || wait > 32*time.Millisecond {
+			break
+		}
+		time.Sleep(wait)
+	}
+	if len(leaked) == 0 {
+		if tracking.Load() {
+			passed(msg, data)
+		}
+		return
+	}
+	stacks := make([]string, len(leaked))
+	for i, g := range leaked {
+		stacks[i] = string(g.stack)
+	}
+	runAssert(msg, append(data,
+		"goroutines.leaked", len(leaked),
+		"goroutines.stacks", strings.Join(stacks, "\n\n"),
+	)...)
+}
+
+func (s *GoroutineSnapshot) leaked() []goroutine {
+	var leaked []goroutine
+	for i, g := range parseGoroutines(allStacks()) {
+		// runtime.Stack lists the calling goroutine first.
+		if i == 0 || s.ids[g.id] || s.ignored(g.stack) {
+			continue
+		}
+		leaked = append(leaked, g)
+	}
+	return leaked
+}
+
+func (s *GoroutineSnapshot) ignored(stack []byte) bool {
+	for _, fn := range s.ignore {
+		for line := range bytes.Lines(stack) {
+			if bytes.HasPrefix(line, []byte(fn)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type goroutine struct {
+	id    uint64
+	stack []byte
+}
+
+// parseGoroutines splits the output of runtime.Stack into goroutines.
+func parseGoroutines(stacks []byte) []goroutine {
+	var gs []goroutine
+	for block := range bytes.SplitSeq(bytes.TrimSpace(stacks), []byte("\n\n")) {
+		header, _, _ := bytes.Cut(block, []byte("\n"))
+		fields := strings.Fields(string(header))
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		id, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		gs = append(gs, goroutine{id: id, stack: block})
+	}
+	return gs
+}
@@ -0,0 +1,172 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Group collects the checks of one WithGroup call. Its methods record
+// violations instead of reporting them.
+type Group struct {
+	checks     int
+	violations []string
+}
+
+// WithGroup runs fn, which makes checks on g, and, if any of them failed,
+// reports a single failure named name listing every violated check, with
+// data as shared context. Use it where seeing all violations at once beats
+// stopping at the first, as when validating a request or a config:
+//
+//	assert.WithGroup("validate request", func(g *assert.Group) {
+//		g.NotNil(req.User, "no user")
+//		g.InRange(req.Limit, 1, 1000, "limit out of range")
+//		g.NoError(req.Cursor.Validate(), "bad cursor")
+//	}, "request", req.ID)
+//
+// The violations are reported in order as "violation.1", "violation.2" and
+// so on.
+func WithGroup(name string, fn func(g *Group), data ...any) {
+	if !Enabled() {
+		return
+	}
+	g := &Group{}
+	fn(g)
+	if len(g.violations) == 0 {
+		if tracking.Load() {
+			passed(name, data)
+		}
+		return
+	}
+	data = append(data[:len(data):len(data)],
+		"group.checks", g.checks,
+		"group.failed", len(g.violations),
+	)
+	for i, v := range g.violations {
+		data = append(data, "violation."+strconv.Itoa(i+1), v)
+	}
+	runAssert(name, data...)
+}
+
+// check counts a check and records it as violated if failed.
+func (g *Group) check(failed bool, msg string, data []any) bool {
+	g.checks++
+	if failed {
+		msg, data = expandMessages(msg, data)
+		r := report{msg: msg, args: data}
+		g.violations = append(g.violations, r.summary())
+	}
+	return !failed
+}
+
+// Assert checks that truth holds, reporting whether it did.
+func (g *Group) Assert(truth bool, msg string, data ...any) bool {
+	return g.check(!truth, msg, data)
+}
+
+// Nil checks that item is nil.
+func (g *Group) Nil(item any, msg string, data ...any) bool {
+	return g.check(item != nil, msg, data)
+}
+
+// NotNil checks that item is neither nil nor a nil pointer.
+func (g *Group) NotNil(item any, msg string, data ...any) bool {
+	return g.check(isNil(item), msg, data)
+}
+
+// NoError checks that err is nil, recording its message if not.
+func (g *Group) NoError(err error, msg string, data ...any) bool {
+	if err != nil {
+		data = append(data[:len(data):len(data)], "error", err)
+	}
+	return g.check(err != nil, msg, data)
+}
+
+// InRange checks that lo <= v <= hi. The values must be strings or
+// numbers; numbers of different types are compared by value, so
+// g.InRange(n, 1, 1000, ...) works whatever the integer type of n. Values
+// that cannot be compared are a violation.
+func (g *Group) InRange(v, lo, hi any, msg string, data ...any) bool {
+	a, aok := compareValues(lo, v)
+	b, bok := compareValues(v, hi)
+	if !aok || !bok {
+		data = append(data[:len(data):len(data)], "value", v, "range", fmt.Sprintf("[%v, %v]", lo, hi), "error", "values cannot be compared")
+		return g.check(true, msg, data)
+	}
+	failed := a > 0 || b > 0
+	if failed {
+		data = append(data[:len(data):len(data)], "value", v, "range", fmt.Sprintf("[%v, %v]", lo, hi))
+	}
+	return g.check(failed, msg, data)
+}
+
+// compareValues compares a and b as cmp.Compare does, reporting false if
+// they are not both strings or both numbers, or one is NaN.
+func compareValues(a, b any) (int, bool) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	ka, kb := numberKind(va), numberKind(vb)
+	switch {
+	case ka == reflect.String && kb == reflect.String:
+		return cmp.Compare(va.String(), vb.String()), true
+	case ka == reflect.Int && kb == reflect.Int:
+		return cmp.Compare(va.Int(), vb.Int()), true
+	case ka == reflect.Uint && kb == reflect.Uint:
+		return cmp.Compare(va.Uint(), vb.Uint()), true
+	case ka == reflect.Int && kb == reflect.Uint:
+		if va.Int() < 0 {
+			return -1, true
+		}
+		return cmp.Compare(uint64(va.Int()), vb.Uint()), true
+	case ka == reflect.Uint && kb == reflect.Int:
+		c, ok := compareValues(b, a)
+		return -c, ok
+	case ka == reflect.Invalid || kb == reflect.Invalid || ka == reflect.String || kb == reflect.String:
+		return 0, false
+	}
+	fa, fb := floatOf(va), floatOf(vb)
+	if math.IsNaN(fa) || math.IsNaN(fb) {
+		return 0, false
+	}
+	return cmp.Compare(fa, fb), true
+}
+
+// numberKind returns reflect.Int, Uint, Float64 or String for values of the
+// kinds of those families, and reflect.Invalid for anything else.
+func numberKind(v reflect.Value) reflect.Kind {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	case reflect.String:
+		return reflect.String
+	}
+	return reflect.Invalid
+}
+
+// floatOf returns the number v as a float64.
+func floatOf(v reflect.Value) float64 {
+	switch numberKind(v) {
+	case reflect.Int:
+		return float64(v.Int())
+	case reflect.Uint:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// Never records a violation unconditionally.
+func (g *Group) Never(msg string, data ...any) {
+	g.check(true, msg, data)
+}
+
+// Failed reports whether any check in the group has failed so far.
+func (g *Group) Failed() bool {
+	return len(g.violations) > 0
+}
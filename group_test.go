@@ -0,0 +1,56 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGroupInRange(t *testing.T) {
+	tests := []struct {
+		v, lo, hi any
+		want      bool
+	}{
+		{5, 1, 10, true},
+		{int64(1000), 1, 1000, true},
+		{int8(-1), uint(0), 10, false},
+		{uint64(math.MaxUint64), 0, math.MaxInt64, false},
+		{2.5, 1, 3, true},
+		{3.5, 1, 3, false},
+		{"m", "a", "z", true},
+		{"m", 1, 3, false},
+		{math.NaN(), 0.0, 1.0, false},
+		{nil, 0, 1, false},
+	}
+	for _, tt := range tests {
+		var g Group
+		if got := g.InRange(tt.v, tt.lo, tt.hi, "out of range"); got != tt.want {
+			t.Errorf("InRange(%v, %v, %v) = %v, want %v", tt.v, tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
+
+func TestWithGroup(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	var b bytes.Buffer
+	SetMode(ModeWarn)
+	ToWriter(&b)
+	WithGroup("validate", func(g *Group) {
+		g.NotNil(nil, "no user")
+		g.InRange(5000, 1, 1000, "limit out of range")
+		g.Assert(true, "fine")
+	})
+	for _, want := range []string{
+		"group.checks=3",
+		"group.failed=2",
+		"violation.1=no user",
+		"violation.2=limit out of range value=5000 range=[1, 1000]",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("report lacks %s:\n%s", want, b.String())
+		}
+	}
+}
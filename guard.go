@@ -0,0 +1,52 @@
+//go:build !tinygo
+
+package assert
+
+import "runtime"
+
+// guardedFunc is the name of Guarded as it appears in stack frames.
+const guardedFunc = pkgPath + ".Guarded"
+
+// Guarded runs fn and returns the assertion failure that made it panic, or
+// nil if it returned normally. Panics other than assertion failures are
+// re-raised.
+//
+// Guarded is meant for recovery middleware in ModePanic. Failures inside fn
+// are not reported when they happen; the caller is expected to add what it
+// knows about the failed operation and write the report with
+// AssertionError.Report. In the other modes failures behave as usual.
+func Guarded(fn func()) (failure *AssertionError) {
+	defer func() {
+		if v := recover(); v != nil {
+			e, ok := v.(*AssertionError)
+			if !ok {
+				panic(v)
+			}
+			failure = e
+		}
+	}()
+	fn()
+	return nil
+}
+
+// maxGuardDepth bounds how far up the stack insideGuarded looks. Failures
+// deeper than that under Guarded are written when they happen, and their
+// AssertionError is marked so that Report does not write them again.
+const maxGuardDepth = 256
+
+// insideGuarded reports whether the calling goroutine is running under
+// Guarded.
+func insideGuarded() bool {
+	var pcs [maxGuardDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if f.Function == guardedFunc {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
@@ -0,0 +1,61 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"runtime"
+	rtmetrics "runtime/metrics"
+)
+
+// HeapBelow asserts that the heap in use, as runtime.MemStats.HeapInuse
+// counts it, is below limit bytes. A service with a memory budget can call
+// it periodically, or from Watch, to fail with diagnostics before the kernel
+// kills it without any. The check reads runtime/metrics and does not stop
+// the world; the report includes the fuller runtime.MemStats figures.
+func HeapBelow(limit uint64, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	if inuse := heapInuse(); inuse >= limit {
+		heapExceeded(limit, inuse, msg, data)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+var heapSamples = [...]string{
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/heap/unused:bytes",
+}
+
+func heapInuse() uint64 {
+	var s [len(heapSamples)]rtmetrics.Sample
+	for i, name := range heapSamples {
+		s[i].Name = name
+	}
+	rtmetrics.Read(s[:])
+	var total uint64
+	for _, v := range s {
+		if v.Value.Kind() == rtmetrics.KindUint64 {
+			total += v.Value.Uint64()
+		}
+	}
+	return total
+}
+
+func heapExceeded(limit, inuse uint64, msg string, data []any) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	runAssert(msg, append(data,
+		"heap.limit", limit,
+		"heap.inuse", inuse,
+		"heap.alloc", ms.HeapAlloc,
+		"heap.sys", ms.HeapSys,
+		"heap.idle", ms.HeapIdle,
+		"heap.released", ms.HeapReleased,
+		"heap.objects", ms.HeapObjects,
+		"heap.next_gc", ms.NextGC,
+		"mem.sys", ms.Sys,
+		"gc.count", ms.NumGC,
+	)...)
+}
@@ -0,0 +1,110 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HeartbeatMonitor fails when the loop it watches stops calling Touch. See
+// Heartbeat.
+type HeartbeatMonitor struct {
+	name    string
+	timeout time.Duration
+	data    []any
+	started time.Time
+
+	last atomic.Int64  // UnixNano of the latest Touch, 0 before the first
+	goid atomic.Uint64 // goroutine of the first Touch
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// Heartbeat starts a background checker that fails if hb.Touch is not
+// called at least once every timeout, turning a silent stall of a loop into
+// a diagnosable failure:
+//
+//	hb, err := assert.Heartbeat("raft-apply-loop", 5*time.Second)
+//	if err != nil {
+//		return err
+//	}
+//	defer hb.Stop()
+//	for entry := range entries {
+//		hb.Touch()
+//		apply(entry)
+//	}
+//
+// The report carries the time of the last touch and the current stack of
+// the goroutine that first touched the heartbeat, which shows where the loop
+// is stuck. A stall is reported once; touching again re-arms the check. data
+// is added to every report. In ModePanic the panic happens on the checker's
+// goroutine and cannot be recovered; in ModeGoexit the checker's goroutine
+// ends and the heartbeat is no longer watched. A non-positive timeout is an
+// error, and nothing is watched.
+func Heartbeat(name string, timeout time.Duration, data ...any) (*HeartbeatMonitor, error) {
+	if timeout <= 0 {
+		return nil, fmt.Errorf("assert: heartbeat %q: non-positive timeout %v", name, timeout)
+	}
+	hb := &HeartbeatMonitor{name: name, timeout: timeout, data: data, started: now(), stop: make(chan struct{})}
+	go hb.run()
+	return hb, nil
+}
+
+// Touch records that the loop is alive. It is cheap enough to call on every
+// iteration.
+func (hb *HeartbeatMonitor) Touch() {
+	if hb.last.Swap(now().UnixNano()) == 0 {
+		hb.goid.Store(currentGoid())
+	}
+}
+
+// Stop ends the checking, for instance when the loop exits normally.
+func (hb *HeartbeatMonitor) Stop() {
+	hb.stopOnce.Do(func() { close(hb.stop) })
+}
+
+func (hb *HeartbeatMonitor) run() {
+	reported := int64(-1) // the touch whose stall was reported
+	for {
+		last := hb.last.Load()
+		since := hb.started
+		if last != 0 {
+			since = time.Unix(0, last)
+		}
+		wait := hb.timeout - now().Sub(since)
+		if wait <= 0 {
+			if last != reported {
+				reported = last
+				hb.fail(since, last != 0)
+			}
+			wait = hb.timeout
+		}
+		select {
+		case <-hb.stop:
+			return
+		case <-clock().After(wait):
+		}
+	}
+}
+
+func (hb *HeartbeatMonitor) fail(since time.Time, touched bool) {
+	data := append(hb.data[:len(hb.data):len(hb.data)],
+		"heartbeat", hb.name,
+		"heartbeat.timeout", hb.timeout.String(),
+	)
+	if !touched {
+		runAssert("heartbeat never started", append(data,
+			"heartbeat.started", since.Format(time.RFC3339Nano),
+		)...)
+		return
+	}
+	runAssert("heartbeat stopped", append(data,
+		"heartbeat.last_touch", since.Format(time.RFC3339Nano),
+		"heartbeat.silent_for", now().Sub(since).Round(time.Millisecond).String(),
+		"heartbeat.goroutine", goroutineStack(hb.goid.Load()),
+	)...)
+}
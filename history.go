@@ -0,0 +1,105 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"unicode/utf8"
+)
+
+// defaultHistorySize is how many failures History keeps unless changed with
+// SetHistorySize.
+const defaultHistorySize = 256
+
+var historyMu sync.Mutex
+var history = make([]Failure, 0, defaultHistorySize)
+var historySize = defaultHistorySize
+var historyNext int // index of the oldest entry once the ring is full
+
+// SetHistorySize sets how many of the most recent failures History keeps,
+// discarding the oldest ones beyond n. Zero, or a negative n, turns the
+// history off.
+func SetHistorySize(n int) {
+	n = max(n, 0)
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	all := historyLocked()
+	if len(all) > n {
+		all = all[len(all)-n:]
+	}
+	history = append(make([]Failure, 0, n), all...)
+	historySize = n
+	historyNext = 0
+}
+
+// History returns the most recent failures that did not end the process,
+// those reported in ModeWarn or raised as panics in ModePanic, oldest first.
+// It lets operators see which soft invariants have been tripping since the
+// process started, for example through asserthttp.HistoryHandler. Failures
+// kept from the output by ReportOnce, the rate limit or the circuit breaker
+// are not recorded. To bound memory, stacks and AssertData dumps are left
+// out and data values are kept as text of at most 1 KiB.
+func History() []Failure {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	return historyLocked()
+}
+
+func historyLocked() []Failure {
+	out := make([]Failure, 0, len(history))
+	out = append(out, history[historyNext:]...)
+	return append(out, history[:historyNext]...)
+}
+
+// historyValueLimit bounds the length of each value kept in the history.
+const historyValueLimit = 1024
+
+// recordHistory adds r to the history. The failure is kept without its stack
+// and AssertData dumps, and with its values formatted as they are in text
+// reports, cut at historyValueLimit bytes: History may be read on another
+// goroutine, such as an HTTP handler's, while the values are still in use
+// by their owner.
+func recordHistory(r *report) {
+	h := *r
+	h.dumps, h.stack = nil, nil
+	f := h.failure()
+	historyText(f.Data)
+	for _, e := range slices.Concat(f.Breadcrumbs, f.Logs) {
+		historyText(e.Data)
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	switch {
+	case historySize == 0:
+	case len(history) < historySize:
+		history = append(history, f)
+	default:
+		history[historyNext] = f
+		historyNext = (historyNext + 1) % historySize
+	}
+}
+
+// historyText replaces the values of the key/value pairs data with their
+// text.
+func historyText(data []any) {
+	for i := 1; i < len(data); i += 2 {
+		var s string
+		if err, ok := data[i].(error); ok {
+			s = err.Error()
+		} else {
+			s = fmt.Sprint(data[i])
+		}
+		if len(s) > historyValueLimit {
+			n := historyValueLimit
+			for n > 0 && !utf8.RuneStart(s[n]) {
+				n--
+			}
+			s = s[:n] + "…"
+		}
+		data[i] = s
+	}
+}
@@ -0,0 +1,60 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	defer SetHistorySize(defaultHistorySize)
+	SetMode(ModeWarn)
+	ToWriter(io.Discard)
+	SetHistorySize(3)
+
+	m := map[string]int{"a": 1}
+	long := strings.Repeat("é", historyValueLimit)
+	Assert(false, "first", "map", m, "err", errors.New("boom"), "long", long)
+	m["b"] = 2 // the history must not see this
+	for _, msg := range []string{"second", "third", "fourth"} {
+		Assert(false, msg)
+	}
+
+	h := History()
+	var msgs []string
+	for _, f := range h {
+		msgs = append(msgs, f.Message)
+		if f.Stack != "" {
+			t.Errorf("%s: stack kept", f.Message)
+		}
+	}
+	if got := strings.Join(msgs, " "); got != "second third fourth" {
+		t.Fatalf("history = %s, want second third fourth", got)
+	}
+
+	SetHistorySize(-1)
+	if h := History(); len(h) != 0 {
+		t.Errorf("history after SetHistorySize(-1) = %d failures, want none", len(h))
+	}
+	SetHistorySize(1)
+	Assert(false, "first", "map", m, "err", errors.New("boom"), "long", long)
+	m["c"] = 3
+	f := History()[0]
+	values := map[string]any{}
+	for _, a := range attrs(f.Data) {
+		values[a.Key] = a.Value
+	}
+	if v := values["map"]; v != "map[a:1 b:2]" {
+		t.Errorf("map = %#v, want its text when recorded", v)
+	}
+	if v := values["err"]; v != "boom" {
+		t.Errorf("err = %#v, want boom", v)
+	}
+	if v, _ := values["long"].(string); len(v) > historyValueLimit+len("…") || !strings.HasSuffix(v, "é…") {
+		t.Errorf("long value kept as %d bytes, want it cut at a rune boundary", len(v))
+	}
+}
@@ -0,0 +1,104 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var hookMu sync.Mutex
+var fatalHooks []func()
+var hookTimeout = 5 * time.Second
+
+// OnFatal registers fn to run after a fatal failure has been reported and
+// before the process exits, e.g. to close listeners or release leader
+// locks. Hooks run in registration order, each bounded by the hook timeout,
+// so a stuck hook cannot keep a crashing process alive.
+func OnFatal(fn func()) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	fatalHooks = append(fatalHooks, fn)
+}
+
+// SetFatalHookTimeout sets how long each OnFatal hook may run. The default
+// is five seconds.
+func SetFatalHookTimeout(d time.Duration) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	hookTimeout = d
+}
+
+func runFatalHooks(c *config) {
+	hookMu.Lock()
+	hooks := append([]func(){}, fatalHooks...)
+	timeout := hookTimeout
+	hookMu.Unlock()
+
+	for i, hook := range hooks {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer func() {
+				if v := recover(); v != nil {
+					fmt.Fprintf(c.output(), "assert: fatal hook %d panicked: %v\n", i, v)
+				}
+			}()
+			hook()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			fmt.Fprintf(c.output(), "assert: fatal hook %d timed out after %v\n", i, timeout)
+		}
+	}
+}
+
+var observers atomic.Pointer[[]func(Failure)]
+
+// OnFailure registers fn to be called for every failed assertion, whatever
+// its mode and including failures that ReportOnce, the rate limit or the
+// circuit breaker keep from being reported. It is the integration point for
+// metrics, tracing annotations and alerting. Observers run synchronously on
+// the failing goroutine, before the report is written, and cannot change the
+// outcome: a panicking observer is ignored. Failures returned by the Check
+// functions are not observed. The stack is captured only for failures that
+// are reported; Stack is empty in those that are kept back.
+func OnFailure(fn func(Failure)) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+
+	var next []func(Failure)
+	if cur := observers.Load(); cur != nil {
+		next = append(next, *cur...)
+	}
+	next = append(next, fn)
+	observers.Store(&next)
+}
+
+// notifyObservers calls the OnFailure observers with r. Observers run
+// before the report is collected, so r's stack is captured here for them if
+// r is to be delivered; a stack walk per failure kept back would be the cost
+// ReportOnce and the rate limit are there to avoid.
+func notifyObservers(c *config, r *report, deliver bool) {
+	obs := observers.Load()
+	if obs == nil {
+		return
+	}
+	if deliver && c.stack && r.stack == nil {
+		r.stack = debug.Stack()
+	}
+	f := r.failure()
+	for _, fn := range *obs {
+		observe(fn, f)
+	}
+}
+
+func observe(fn func(Failure), f Failure) {
+	defer func() { recover() }()
+	fn(f)
+}
@@ -0,0 +1,78 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// SetInteractive makes failures that would exit or panic ask on the
+// terminal what to do once the report is written: continue past the
+// failure, abort as usual, dump all goroutines, print the failing stack or,
+// when a debugger is attached, break into it. It only applies when stdin and stderr are both
+// terminals, so it is safe to leave on in development builds; it is off by
+// default.
+func SetInteractive(on bool) {
+	updateConfig(func(c *config) { c.interactive = on })
+}
+
+// promptMu keeps concurrent failures from prompting at the same time.
+var promptMu sync.Mutex
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// promptContinue asks whether to continue past the failure r and reports
+// whether the answer was yes. It returns false without asking when there is
+// no terminal to ask on.
+func promptContinue(r *report) bool {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+		return false
+	}
+	promptMu.Lock()
+	defer promptMu.Unlock()
+
+	in := bufio.NewReader(os.Stdin)
+	fmt.Fprintf(os.Stderr, "assert: %q failed at %s\n", r.msg, r.site)
+	for {
+		// Offered only under a debugger: without one, the breakpoint
+		// trap kills the process.
+		attached, _ := debuggerAttached()
+		if attached {
+			fmt.Fprint(os.Stderr, "[c]ontinue, [a]bort, [d]ump goroutines, [s]tack, [b]reak into debugger? ")
+		} else {
+			fmt.Fprint(os.Stderr, "[c]ontinue, [a]bort, [d]ump goroutines, [s]tack? ")
+		}
+		line, err := in.ReadString('\n')
+		if err != nil {
+			return false
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "c", "continue":
+			return true
+		case "a", "abort":
+			return false
+		case "d", "dump":
+			os.Stderr.Write(allStacks())
+		case "s", "stack":
+			if r.stack != nil {
+				os.Stderr.Write(r.stack)
+			} else {
+				fmt.Fprintln(os.Stderr, "stacks are disabled")
+			}
+		case "b", "break":
+			if attached {
+				runtime.Breakpoint()
+			} else {
+				fmt.Fprintln(os.Stderr, "no debugger is attached")
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package term holds the terminal output shared by the report commands:
+// colouring and the escaping of text read from reports.
+package term
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ANSI escape sequences.
+const (
+	Reset  = "\x1b[0m"
+	Bold   = "\x1b[1m"
+	Dim    = "\x1b[2m"
+	Red    = "\x1b[31m"
+	Green  = "\x1b[32m"
+	Yellow = "\x1b[33m"
+	Blue   = "\x1b[34m"
+	Cyan   = "\x1b[36m"
+)
+
+// Color resolves the value of a -color flag, "auto", "always" or "never",
+// for output to f. Auto colours terminals unless NO_COLOR is set or TERM is
+// dumb.
+func Color(flag string, f *os.File) (bool, error) {
+	switch flag {
+	case "auto":
+		return isTerminal(f) && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb", nil
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown -color %q", flag)
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Printer writes to W, colouring text if Color is set.
+type Printer struct {
+	W     io.Writer
+	Color bool
+}
+
+// Paint returns s in style, or s itself if p does not colour or s is empty.
+func (p Printer) Paint(style, s string) string {
+	if !p.Color || s == "" {
+		return s
+	}
+	return style + s + Reset
+}
+
+// Clean escapes the control characters in s other than newlines and tabs,
+// and bidirectional formatting characters, as Go quotes them. Reports carry
+// text from untrusted input, such as request bodies, which must not be able
+// to send escape sequences to the terminal of whoever reads them.
+func Clean(s string) string {
+	if !strings.ContainsFunc(s, escaped) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if escaped(r) {
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func escaped(r rune) bool {
+	return r != '\n' && r != '\t' && unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r)
+}
@@ -0,0 +1,157 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Invariantable is implemented by types that can check their own invariants.
+// Invariant returns nil when the value is consistent.
+type Invariantable interface {
+	Invariant() error
+}
+
+var invariantableType = reflect.TypeFor[Invariantable]()
+
+// CheckInvariants fails if obj implements Invariantable and its Invariant
+// method returns an error, letting domain types own their invariants while
+// call sites stay one line:
+//
+//	assert.CheckInvariants(order, "order corrupted after applying discount")
+func CheckInvariants(obj any, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	inv, ok := obj.(Invariantable)
+	if !ok {
+		return
+	}
+	if err := inv.Invariant(); err != nil {
+		runAssert(msg, append(data, "type", fmt.Sprintf("%T", obj), "error", err)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// CheckInvariantsDeep is CheckInvariants that also checks every value
+// reachable through exported fields, pointers, slices, arrays and maps. The
+// report names the path to the first inconsistent value, e.g.
+// "Lines[2]".
+func CheckInvariantsDeep(obj any, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	w := invariantWalker{seen: map[visit]bool{}}
+	if path, typ, err := w.walk(reflect.ValueOf(obj), "", true); err != nil {
+		runAssert(msg, append(data, "path", path, "type", typ, "error", err)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+type invariantWalker struct {
+	seen map[visit]bool
+}
+
+// visit identifies a pointer, map or slice the walker has been through, so
+// that shared values are checked once and cycles end. Slices are told apart
+// by length as well, as two of them may share a backing array.
+type visit struct {
+	p   unsafe.Pointer
+	typ reflect.Type
+	len int
+}
+
+// walk checks v, unless check is false, and everything reachable from it,
+// returning the path and type of the first value whose invariant fails.
+func (w *invariantWalker) walk(v reflect.Value, path string, check bool) (string, string, error) {
+	if !v.IsValid() {
+		return "", "", nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		if v.IsNil() {
+			return "", "", nil
+		}
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		key := visit{p: v.UnsafePointer(), typ: v.Type()}
+		if v.Kind() == reflect.Slice {
+			key.len = v.Len()
+		}
+		if w.seen[key] {
+			return "", "", nil
+		}
+		w.seen[key] = true
+	}
+
+	checked := false
+	if check {
+		var err error
+		if checked, err = checkValue(v); err != nil {
+			if path == "" {
+				path = "."
+			}
+			return path, v.Type().String(), err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		// The method set of a pointer includes its element's, so an
+		// element already checked through its pointer is not checked again.
+		return w.walk(v.Elem(), path, !checked)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if p, typ, err := w.walk(v.Field(i), joinPath(path, t.Field(i).Name), true); err != nil {
+				return p, typ, err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if p, typ, err := w.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), true); err != nil {
+				return p, typ, err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if p, typ, err := w.walk(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), true); err != nil {
+				return p, typ, err
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// checkValue calls Invariant on v if v, or a pointer to it, implements
+// Invariantable, and reports whether it did. Values reached through
+// unexported fields are skipped.
+func checkValue(v reflect.Value) (bool, error) {
+	if !v.CanInterface() {
+		return false, nil
+	}
+	if v.Type().Implements(invariantableType) {
+		return true, v.Interface().(Invariantable).Invariant()
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(invariantableType) {
+		return true, v.Addr().Interface().(Invariantable).Invariant()
+	}
+	return false, nil
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
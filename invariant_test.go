@@ -0,0 +1,70 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"errors"
+	"testing"
+)
+
+type account struct {
+	Balance int
+	Owner   *owner
+	Tags    []any
+	Meta    map[string]any
+}
+
+func (a account) Invariant() error {
+	if a.Balance < 0 {
+		return errors.New("negative balance")
+	}
+	return nil
+}
+
+type owner struct {
+	Name    string
+	Account *account
+}
+
+func (o *owner) Invariant() error {
+	if o.Name == "" {
+		return errors.New("no name")
+	}
+	return nil
+}
+
+func TestCheckInvariantsDeep(t *testing.T) {
+	selfSlice := []any{nil}
+	selfSlice[0] = selfSlice
+	selfMap := map[string]any{}
+	selfMap["self"] = selfMap
+
+	tests := []struct {
+		name  string
+		obj   func() any
+		fails bool
+	}{
+		{"valid", func() any { return &account{Balance: 1, Owner: &owner{Name: "a"}} }, false},
+		{"top level", func() any { return account{Balance: -1} }, true},
+		{"nested", func() any { return &account{Owner: &owner{}} }, true},
+		{"in slice", func() any { return []account{{}, {Balance: -1}} }, true},
+		{"in map", func() any { return map[int]*owner{1: {}} }, true},
+		{"pointer cycle", func() any {
+			a := &account{Owner: &owner{Name: "a"}}
+			a.Owner.Account = a
+			return a
+		}, false},
+		{"slice cycle", func() any { return account{Tags: selfSlice} }, false},
+		{"map cycle", func() any { return account{Meta: selfMap} }, false},
+		{"after cycle", func() any {
+			return account{Tags: selfSlice, Meta: map[string]any{"o": &owner{}}}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fails(t, func() { CheckInvariantsDeep(tt.obj(), "invariant") }); got != tt.fails {
+				t.Errorf("failed = %v, want %v", got, tt.fails)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const journalSocket = "/run/systemd/journal/socket"
+
+type journalConn struct {
+	conn  *net.UnixConn
+	ident string
+}
+
+// ToJournal sends reports to the systemd journal through its native
+// protocol instead of the configured writer. Every key/value pair becomes a
+// journal field, so they can be queried with journalctl, e.g.
+// "journalctl AREA=Payments". Keys that would set a field the journal
+// reserves, such as MESSAGE, PRIORITY or CODE_FILE, get the prefix ASSERT_.
+// Entries have priority crit, and when the journal cannot be written to the
+// report falls back to the writer. Calling ToWriter switches back. It is
+// only available on Linux.
+func ToJournal() error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("assert: connecting to the journal: %w", err)
+	}
+	j := &journalConn{conn: conn, ident: filepath.Base(os.Args[0])}
+	updateConfig(func(c *config) { c.journal = j })
+	return nil
+}
+
+func (j *journalConn) send(r *report) error {
+	b := getBuf()
+	defer putBuf(b)
+
+	r.journalEntry(b, j.ident)
+	_, err := j.conn.Write(b.Bytes())
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		return j.sendFile(b.Bytes())
+	}
+	return err
+}
+
+// sendFile passes an entry too large for a datagram as a file descriptor,
+// as the native protocol allows.
+func (j *journalConn) sendFile(entry []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "assert-journal-")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	os.Remove(f.Name())
+
+	if _, err := f.Write(entry); err != nil {
+		return err
+	}
+	_, _, err = j.conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), nil)
+	return err
+}
+
+// journalEntry writes r in the journal's native format.
+func (r *report) journalEntry(b *bytes.Buffer, ident string) {
+	writeJournalField(b, "MESSAGE", "assertion failed: "+r.summary())
+	writeJournalField(b, "PRIORITY", "2")
+	writeJournalField(b, "SYSLOG_IDENTIFIER", ident)
+	if r.site.file != "" {
+		writeJournalField(b, "CODE_FILE", r.site.file)
+		writeJournalField(b, "CODE_LINE", strconv.Itoa(r.site.line))
+	}
+	pairs := r.pairs()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if name := journalFieldName(fmt.Sprint(pairs[i])); name != "" {
+			writeJournalField(b, name, fmt.Sprint(pairs[i+1]))
+		}
+	}
+	if r.stack != nil {
+		writeJournalField(b, "STACK", string(r.stack))
+	}
+}
+
+// writeJournalField writes one field, using the length-prefixed form for
+// values that contain newlines.
+func writeJournalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journalReserved holds the fields journalEntry writes itself and those
+// journald gives a meaning to. Report keys are not allowed to set them.
+var journalReserved = map[string]bool{
+	"MESSAGE":            true,
+	"MESSAGE_ID":         true,
+	"PRIORITY":           true,
+	"ERRNO":              true,
+	"INVOCATION_ID":      true,
+	"USER_INVOCATION_ID": true,
+	"SYSLOG_FACILITY":    true,
+	"SYSLOG_IDENTIFIER":  true,
+	"SYSLOG_PID":         true,
+	"SYSLOG_TIMESTAMP":   true,
+	"SYSLOG_RAW":         true,
+	"DOCUMENTATION":      true,
+	"TID":                true,
+	"UNIT":               true,
+	"USER_UNIT":          true,
+	"STACK":              true,
+}
+
+// journalFieldName converts a report key to a valid journal field name:
+// upper case letters, digits and underscores, not starting with an
+// underscore or digit, at most 64 bytes. Keys that would name a reserved
+// field, or one starting with CODE_ or OBJECT_, get the prefix ASSERT_.
+func journalFieldName(key string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(key) {
+		if c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	name := strings.TrimLeft(b.String(), "_0123456789")
+	if journalReserved[name] || strings.HasPrefix(name, "CODE_") || strings.HasPrefix(name, "OBJECT_") {
+		name = "ASSERT_" + name
+	}
+	return name[:min(len(name), 64)]
+}
@@ -0,0 +1,36 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJournalReservedKeys(t *testing.T) {
+	var b bytes.Buffer
+	testReport("message", "spoofed", "priority", 7, "code_file", "x.go", "user", "ann").journalEntry(&b, "test")
+	fields := map[string][]string{}
+	for _, line := range strings.Split(b.String(), "\n") {
+		if name, value, ok := strings.Cut(line, "="); ok {
+			fields[name] = append(fields[name], value)
+		}
+	}
+	for name, want := range map[string]string{
+		"PRIORITY":          "2",
+		"CODE_FILE":         "queue.go",
+		"ASSERT_MESSAGE":    "spoofed",
+		"ASSERT_PRIORITY":   "7",
+		"ASSERT_CODE_FILE":  "x.go",
+		"USER":              "ann",
+		"SYSLOG_IDENTIFIER": "test",
+	} {
+		if got := fields[name]; len(got) != 1 || got[0] != want {
+			t.Errorf("%s = %q, want only %q", name, got, want)
+		}
+	}
+	if got := fields["MESSAGE"]; len(got) != 1 || !strings.Contains(got[0], "queue depth exceeded") {
+		t.Errorf("MESSAGE = %q, want only the report's", got)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !tinygo
+
+package assert
+
+import "errors"
+
+type journalConn struct{}
+
+// ToJournal sends reports to the systemd journal. It is only available on
+// Linux and fails elsewhere.
+func ToJournal() error {
+	return errors.New("assert: the systemd journal is only available on Linux")
+}
+
+func (j *journalConn) send(r *report) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,43 @@
+//go:build js && wasm && !tinygo
+
+package assert
+
+import (
+	"io"
+	"sync/atomic"
+	"syscall/js"
+)
+
+// In the browser exiting stops the Go program without a trace, so failures
+// panic by default and reports go to a JavaScript callback rather than
+// stderr.
+const defaultMode = ModePanic
+
+var jsCallback atomic.Pointer[js.Value]
+
+// SetJSCallback makes reports written to the default output go to fn, a
+// JavaScript function called with the report as a string. The default is
+// console.error. A value that is not a function restores the default.
+func SetJSCallback(fn js.Value) {
+	if fn.Type() != js.TypeFunction {
+		jsCallback.Store(nil)
+		return
+	}
+	jsCallback.Store(&fn)
+}
+
+func defaultOutput() io.Writer {
+	return jsWriter{}
+}
+
+// jsWriter passes each write to the registered callback.
+type jsWriter struct{}
+
+func (jsWriter) Write(p []byte) (int, error) {
+	if fn := jsCallback.Load(); fn != nil {
+		fn.Invoke(string(p))
+	} else {
+		js.Global().Get("console").Call("error", string(p))
+	}
+	return len(p), nil
+}
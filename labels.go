@@ -0,0 +1,84 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bufio"
+	"bytes"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// IncludeProfileLabels controls whether reports include the pprof labels of
+// the failing goroutine, as set with pprof.Do or pprof.SetGoroutineLabels,
+// as "pprof.<key>" fields. Reading them takes a goroutine profile, which
+// stops the world and costs time proportional to the number of goroutines,
+// so it is off by default.
+func IncludeProfileLabels(on bool) {
+	updateConfig(func(c *config) { c.labels = on })
+}
+
+// labelsMu makes sure at most one goroutine is inside the profile at a time,
+// so the record being written is the caller's own.
+var labelsMu sync.Mutex
+
+// goroutineLabels returns the pprof labels of the calling goroutine as
+// "pprof.<key>", value pairs. The runtime only exposes them through the
+// goroutine profile, so it is scanned for the record of the goroutine that is
+// writing the profile.
+func goroutineLabels() []any {
+	labelsMu.Lock()
+	defer labelsMu.Unlock()
+
+	var b bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&b, 1); err != nil {
+		return nil
+	}
+
+	var labels string
+	sc := bufio.NewScanner(&b)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case !strings.HasPrefix(line, "#"):
+			// A blank line or the start of the next record.
+			labels = ""
+		case strings.HasPrefix(line, "# labels: "):
+			labels = strings.TrimPrefix(line, "# labels: ")
+		case strings.Contains(line, "runtime/pprof.writeGoroutine"):
+			return parseLabels(labels)
+		}
+	}
+	return nil
+}
+
+// parseLabels parses the goroutine profile's {"key":"value", ...} form.
+func parseLabels(s string) []any {
+	s = strings.TrimPrefix(s, "{")
+	var pairs []any
+	for {
+		s = strings.TrimLeft(s, ", ")
+		key, rest, ok := unquotePrefix(s)
+		if !ok || !strings.HasPrefix(rest, ":") {
+			return pairs
+		}
+		value, rest, ok := unquotePrefix(rest[1:])
+		if !ok {
+			return pairs
+		}
+		pairs = append(pairs, "pprof."+key, value)
+		s = rest
+	}
+}
+
+func unquotePrefix(s string) (string, string, bool) {
+	q, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return "", s, false
+	}
+	v, err := strconv.Unquote(q)
+	return v, s[len(q):], err == nil
+}
@@ -0,0 +1,140 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WatchedLock wraps a sync.Locker and asserts that no critical section holds
+// it longer than a bound. It is meant for development builds, where it
+// catches slow critical sections and, because it also fires while the lock
+// is still held, deadlocks and priority inversions.
+type WatchedLock struct {
+	l     sync.Locker
+	bound time.Duration
+	data  []any
+
+	mu       sync.Mutex // guards the holder fields below
+	acquired time.Time
+	goid     uint64
+	pcs      [32]uintptr
+	npcs     int
+	reported bool
+	timer    *time.Timer
+}
+
+// WatchLock returns l wrapped to fail when it is held longer than bound.
+// Use the result in place of l:
+//
+//	var mu sync.Mutex
+//	l := assert.WatchLock(&mu, 100*time.Millisecond, assert.AreaKey, "cache")
+//
+//	l.Lock()
+//	defer l.Unlock()
+//
+// The report names how long the lock has been held and where it was
+// acquired. When the bound passes while the lock is still held, a warning
+// with the holder's current stack is written at once, whatever the mode;
+// the overrun is then reported in the configured mode by Unlock, on the
+// holder's goroutine. data is added to every report.
+func WatchLock(l sync.Locker, bound time.Duration, data ...any) *WatchedLock {
+	// Clipped so that the reports of Unlock and the timer, which can run at
+	// once, never append to the same array.
+	return &WatchedLock{l: l, bound: bound, data: slices.Clip(data)}
+}
+
+// Lock locks the underlying lock and starts timing the critical section.
+func (w *WatchedLock) Lock() {
+	w.l.Lock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.acquired = time.Now()
+	w.goid = currentGoid()
+	w.npcs = runtime.Callers(2, w.pcs[:])
+	w.reported = false
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.bound, w.expired)
+	} else {
+		w.timer.Reset(w.bound)
+	}
+}
+
+// Unlock unlocks the underlying lock, failing if it was held longer than the
+// bound. Unlocking a lock that was not locked through w reports nothing.
+func (w *WatchedLock) Unlock() {
+	w.mu.Lock()
+	held := time.Since(w.acquired)
+	report := !w.acquired.IsZero() && held > w.bound
+	acquiredAt := w.acquiredAt()
+	w.acquired = time.Time{}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	w.l.Unlock()
+
+	if report {
+		runAssert("lock held longer than bound", append(w.data,
+			"lock.bound", w.bound.String(),
+			"lock.held", held.String(),
+			"lock.acquired_at", acquiredAt,
+		)...)
+	}
+}
+
+// expired runs on the timer's goroutine when the lock has been held for the
+// bound. It only warns: panicking there would crash the process.
+func (w *WatchedLock) expired() {
+	w.mu.Lock()
+	if w.acquired.IsZero() || w.reported || time.Since(w.acquired) < w.bound {
+		// Unlocked, or relocked, after the timer fired.
+		w.mu.Unlock()
+		return
+	}
+	w.reported = true
+	held := time.Since(w.acquired)
+	goid := w.goid
+	acquiredAt := w.acquiredAt()
+	w.mu.Unlock()
+
+	warnAssert("lock held longer than bound", append(w.data,
+		"lock.bound", w.bound.String(),
+		"lock.held", held.String()+" and counting",
+		"lock.acquired_at", acquiredAt,
+		"lock.holder", goroutineStack(goid),
+	)...)
+}
+
+// acquiredAt formats the stack recorded by Lock. w.mu must be held.
+func (w *WatchedLock) acquiredAt() string {
+	return formatStack(w.pcs[:w.npcs])
+}
+
+// currentGoid returns the calling goroutine's ID, parsed from the header of
+// its stack trace.
+func currentGoid() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := bytes.Fields(buf[:n])
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseUint(string(fields[1]), 10, 64)
+	return id
+}
+
+// goroutineStack returns the current stack of goroutine id.
+func goroutineStack(id uint64) string {
+	for _, g := range parseGoroutines(allStacks()) {
+		if g.id == id {
+			return string(g.stack)
+		}
+	}
+	return "exited"
+}
@@ -0,0 +1,54 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchedLock(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	var mu sync.Mutex
+	l := WatchLock(&mu, 10*time.Millisecond)
+
+	mu.Lock()
+	if fails(t, l.Unlock) {
+		t.Error("Unlock of a lock not locked through WatchLock failed")
+	}
+
+	// The timer fires while the lock is held; in ModePanic it must warn
+	// rather than panic on its own goroutine, and leave the failure to Unlock.
+	var b syncBuffer
+	SetMode(ModePanic)
+	ToWriter(&b)
+	l.Lock()
+	time.Sleep(50 * time.Millisecond)
+	if !strings.Contains(b.String(), "and counting") {
+		t.Errorf("no warning while the lock was held:\n%s", b.String())
+	}
+	if !fails(t, l.Unlock) {
+		t.Error("Unlock after the bound did not fail")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
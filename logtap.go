@@ -0,0 +1,159 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logRecord is a warning or error logged through a handler from TapSlog.
+type logRecord struct {
+	time  time.Time
+	level slog.Level
+	msg   string
+	data  []any
+}
+
+func (l logRecord) String() string {
+	var s strings.Builder
+	s.WriteString(l.time.Format("15:04:05.000000"))
+	s.WriteByte(' ')
+	s.WriteString(l.level.String())
+	s.WriteByte(' ')
+	s.WriteString(l.msg)
+	for i := 0; i+1 < len(l.data); i += 2 {
+		fmt.Fprintf(&s, " %v=%v", l.data[i], l.data[i+1])
+	}
+	return s.String()
+}
+
+var logsMu sync.Mutex
+var logRing []logRecord // nil until TapSlog is called
+var logNext int
+
+// TapSlog wraps next so that the last n records of level Warn and above
+// handled through it are kept and included in every failure report, since
+// the errors logged just before an invariant broke are often its cause.
+// Records are passed on to next unchanged.
+//
+//	slog.SetDefault(slog.New(assert.TapSlog(slog.Default().Handler(), 20)))
+//
+// Every tapped handler shares one buffer, whose size is set by the latest
+// call.
+func TapSlog(next slog.Handler, n int) slog.Handler {
+	logsMu.Lock()
+	defer logsMu.Unlock()
+
+	if n < 1 {
+		n = 1
+	}
+	if n != len(logRing) {
+		old := recentLogsLocked()
+		logRing = make([]logRecord, n)
+		logNext = 0
+		if len(old) > n {
+			old = old[len(old)-n:]
+		}
+		for _, l := range old {
+			logRing[logNext] = l
+			logNext = (logNext + 1) % n
+		}
+	}
+	return &tapHandler{next: next}
+}
+
+type tapHandler struct {
+	next   slog.Handler
+	attrs  []any  // key/value pairs added with WithAttrs
+	prefix string // group prefix for keys, e.g. "req."
+}
+
+func (h *tapHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn || h.next.Enabled(ctx, level)
+}
+
+func (h *tapHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		l := logRecord{time: r.Time, level: r.Level, msg: r.Message, data: slices.Clone(h.attrs)}
+		if l.time.IsZero() {
+			l.time = now()
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			l.data = appendAttr(l.data, h.prefix, a)
+			return true
+		})
+		recordLog(l)
+	}
+	if !h.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *tapHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	data := slices.Clone(h.attrs)
+	for _, a := range attrs {
+		data = appendAttr(data, h.prefix, a)
+	}
+	return &tapHandler{next: h.next.WithAttrs(attrs), attrs: data, prefix: h.prefix}
+}
+
+func (h *tapHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &tapHandler{next: h.next.WithGroup(name), attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// appendAttr appends a to data as key/value pairs, flattening groups into
+// dotted keys.
+func appendAttr(data []any, prefix string, a slog.Attr) []any {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, g := range v.Group() {
+			data = appendAttr(data, prefix, g)
+		}
+		return data
+	}
+	if a.Key == "" {
+		return data
+	}
+	return append(data, prefix+a.Key, v.Any())
+}
+
+func recordLog(l logRecord) {
+	logsMu.Lock()
+	defer logsMu.Unlock()
+	if len(logRing) == 0 {
+		return
+	}
+	logRing[logNext] = l
+	logNext = (logNext + 1) % len(logRing)
+}
+
+// recentLogs returns the kept log records, oldest first.
+func recentLogs() []logRecord {
+	logsMu.Lock()
+	defer logsMu.Unlock()
+	return recentLogsLocked()
+}
+
+func recentLogsLocked() []logRecord {
+	var out []logRecord
+	for i := range logRing {
+		l := logRing[(logNext+i)%len(logRing)]
+		if !l.time.IsZero() {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+//go:build !tinygo
+
+package assert
+
+// Message builds a structured failure description. Pass it among an
+// assertion's data, where every assertion accepts it:
+//
+//	assert.Assert(size <= limit, "compaction invariant",
+//		assert.Msg("level too large").Expected(limit).Actual(size).Hint("check level sizes"))
+//
+// Its text is appended to the assertion's message, and its parts become the
+// report's expected, actual and hint fields, so failures read the same
+// whoever wrote them. Building a Message allocates, so in hot paths build it
+// only once the condition has failed.
+type Message struct {
+	text  string
+	pairs []any
+}
+
+// Msg starts a Message with text, which may be empty.
+func Msg(text string) *Message {
+	return &Message{text: text}
+}
+
+// Expected records the value the invariant required.
+func (m *Message) Expected(v any) *Message {
+	return m.With("expected", v)
+}
+
+// Actual records the value found instead.
+func (m *Message) Actual(v any) *Message {
+	return m.With("actual", v)
+}
+
+// Hint records a suggestion for whoever investigates the failure.
+func (m *Message) Hint(hint string) *Message {
+	return m.With("hint", hint)
+}
+
+// With records any other key/value pair.
+func (m *Message) With(key string, v any) *Message {
+	m.pairs = append(m.pairs, key, v)
+	return m
+}
+
+// expandMessages replaces every Message and Metadata in data by its pairs,
+// in one pass, and appends the text of the Messages to msg. Nil ones are
+// dropped. data is returned as it is if it holds neither.
+func expandMessages(msg string, data []any) (string, []any) {
+	var out []any
+	for i, v := range data {
+		var pairs []any
+		switch x := v.(type) {
+		case *Message:
+			if x != nil {
+				pairs = x.pairs
+				switch {
+				case x.text == "":
+				case msg == "":
+					msg = x.text
+				default:
+					msg += ": " + x.text
+				}
+			}
+		case *Metadata:
+			if x != nil {
+				pairs = x.pairs
+			}
+		default:
+			if out != nil {
+				out = append(out, v)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]any, 0, len(data)+len(pairs)), data[:i]...)
+		}
+		out = append(out, pairs...)
+	}
+	if out == nil {
+		return msg, data
+	}
+	return msg, out
+}
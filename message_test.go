@@ -0,0 +1,28 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandMessages(t *testing.T) {
+	meta := Meta("owner", "payments")
+	var nilMsg *Message
+	msg, data := expandMessages("ledger", []any{
+		"txn", 7, meta, Msg("unbalanced").Expected(0).Actual(3), nilMsg, (*Metadata)(nil),
+	})
+	if msg != "ledger: unbalanced" {
+		t.Errorf("msg = %q, want ledger: unbalanced", msg)
+	}
+	want := []any{"txn", 7, "meta.owner", "payments", "expected", 0, "actual", 3}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %#v, want %#v", data, want)
+	}
+
+	plain := []any{"n", 1}
+	if _, got := expandMessages("m", plain); &got[0] != &plain[0] {
+		t.Error("data without a Message or Metadata was copied")
+	}
+}
@@ -0,0 +1,92 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Metadata is static information about assertions, such as the owning team,
+// a ticket or a design document, that failures carry so on-call can find
+// who and what to consult. Build it once and pass it among the data of the
+// assertions it describes, or attach it to an area with SetAreaMeta:
+//
+//	var ledgerMeta = assert.Meta("owner", "payments-team", "doc", "https://wiki/ledger-invariants")
+//
+//	assert.Assert(debits == credits, "ledger unbalanced", ledgerMeta, "txn", id)
+//
+// Its pairs are reported with a "meta." prefix, e.g. meta.owner=payments-team.
+// Passing a package level Metadata does not allocate.
+type Metadata struct {
+	pairs []any
+}
+
+// Meta returns Metadata holding the key/value pairs kv.
+func Meta(kv ...string) *Metadata {
+	m := &Metadata{pairs: make([]any, 0, len(kv)+1)}
+	for i := 0; i < len(kv); i += 2 {
+		v := ""
+		if i+1 < len(kv) {
+			v = kv[i+1]
+		}
+		m.pairs = append(m.pairs, "meta."+kv[i], v)
+	}
+	return m
+}
+
+var areaMeta atomic.Pointer[map[string]*Metadata]
+
+// SetAreaMeta attaches m to every failure in area and its sub-areas. The
+// metadata of the most specific area applies. A nil m removes it.
+func SetAreaMeta(area string, m *Metadata) {
+	areaMu.Lock()
+	defer areaMu.Unlock()
+
+	next := map[string]*Metadata{}
+	if cur := areaMeta.Load(); cur != nil {
+		for k, v := range *cur {
+			next[k] = v
+		}
+	}
+	if m == nil {
+		delete(next, area)
+	} else {
+		next[area] = m
+	}
+	areaMeta.Store(&next)
+}
+
+// metaForArea returns the pairs of the metadata attached to area.
+func metaForArea(area string) []any {
+	metas := areaMeta.Load()
+	if metas == nil {
+		return nil
+	}
+	for {
+		if m, ok := (*metas)[area]; ok {
+			return m.pairs
+		}
+		i := strings.LastIndexByte(area, '.')
+		if i < 0 {
+			return nil
+		}
+		area = area[:i]
+	}
+}
+
+// withAreaMeta appends the metadata attached to area to data, skipping keys
+// the assertion already set.
+func withAreaMeta(area string, data []any) []any {
+	pairs := metaForArea(area)
+	if len(pairs) == 0 {
+		return data
+	}
+	out := append(make([]any, 0, len(data)+len(pairs)), data...)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if _, _, ok := takeKey(data, pairs[i].(string)); !ok {
+			out = append(out, pairs[i], pairs[i+1])
+		}
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics receives counts and timings from the package, so any metrics
+// library can be wired in without this package depending on it. Methods are
+// called on the goroutine evaluating the assertion and must be fast and safe
+// for concurrent use.
+type Metrics interface {
+	// IncEvaluated is called for every evaluated assertion, passing or not,
+	// in an enabled area.
+	IncEvaluated(area string)
+	// IncFailed is called for every failed assertion that is not ignored by
+	// DisableArea, the policy or a suppression.
+	IncFailed(area string, severity Severity)
+	// ObserveReportDuration is called with the time it took to build and
+	// write a report.
+	ObserveReportDuration(d time.Duration)
+}
+
+type metricsBox struct {
+	m Metrics
+}
+
+var metrics atomic.Pointer[metricsBox]
+
+// sitesOn is set by TrackSites.
+var sitesOn atomic.Bool
+
+// trackingMu serializes updates of tracking.
+var trackingMu sync.Mutex
+
+// SetMetrics makes the package report to m. While metrics are set, passing
+// assertions leave their inlined fast path to be counted. A nil m, the
+// default, turns metrics off.
+func SetMetrics(m Metrics) {
+	if m == nil {
+		metrics.Store(nil)
+	} else {
+		metrics.Store(&metricsBox{m: m})
+	}
+	updateTracking()
+}
+
+// updateTracking sets tracking if passing assertions must be observed.
+func updateTracking() {
+	trackingMu.Lock()
+	defer trackingMu.Unlock()
+	tracking.Store(sitesOn.Load() || metrics.Load() != nil)
+}
+
+// passed observes a passing assertion. Assertions call it when tracking is
+// on, keeping their passing path to a single extra load.
+func passed(msg string, data []any) {
+	if !Enabled() {
+		return
+	}
+	area := areaOf(data)
+	if !AreaEnabled(area) {
+		return
+	}
+	if sitesOn.Load() {
+		trackSite(msg, area).evals.Add(1)
+	}
+	if b := metrics.Load(); b != nil {
+		b.m.IncEvaluated(area)
+	}
+}
+
+// trackFailure observes a failure, adding the site's counts to its report.
+// runAssert calls it once the failure has passed the area, policy and
+// suppression filters, so ignored failures are not counted.
+func trackFailure(r *report) {
+	if sitesOn.Load() {
+		rec := trackSite(r.msg, r.area)
+		rec.evals.Add(1)
+		r.siteFailures = rec.fails.Add(1)
+		r.siteEvals = rec.evals.Load()
+	}
+	if b := metrics.Load(); b != nil {
+		b.m.IncEvaluated(r.area)
+		b.m.IncFailed(r.area, r.severity)
+	}
+}
+
+// observeReport reports the time spent on a report started at start.
+func observeReport(start time.Time) {
+	if b := metrics.Load(); b != nil {
+		b.m.ObserveReportDuration(time.Since(start))
+	}
+}
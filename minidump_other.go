@@ -0,0 +1,9 @@
+//go:build !tinygo && !windows
+
+package assert
+
+import "errors"
+
+func writeMinidump(path string) error {
+	return errors.New("minidumps are only supported on Windows")
+}
@@ -0,0 +1,46 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"os"
+	"syscall"
+)
+
+var procMiniDumpWriteDump = syscall.NewLazyDLL("dbghelp.dll").NewProc("MiniDumpWriteDump")
+
+// MINIDUMP_TYPE flags: data segments, handles and thread information, which
+// is enough for stacks and globals without dumping the whole heap.
+const (
+	miniDumpWithDataSegs   = 0x00000001
+	miniDumpWithHandleData = 0x00000004
+	miniDumpWithThreadInfo = 0x00001000
+)
+
+// writeMinidump writes a minidump of the current process to path.
+func writeMinidump(path string) error {
+	if err := procMiniDumpWriteDump.Find(); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return err
+	}
+	ok, _, err := procMiniDumpWriteDump.Call(
+		uintptr(process),
+		uintptr(os.Getpid()),
+		f.Fd(),
+		miniDumpWithDataSegs|miniDumpWithHandleData|miniDumpWithThreadInfo,
+		0, 0, 0,
+	)
+	if ok == 0 {
+		return err
+	}
+	return nil
+}
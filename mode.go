@@ -0,0 +1,99 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Mode controls what happens after a failed assertion has been reported.
+type Mode int
+
+const (
+	// ModeExit terminates the process with exit code 1. This is the default
+	// everywhere but js/wasm, where it is ModePanic.
+	ModeExit Mode = iota
+	// ModePanic panics, allowing the failure to be recovered.
+	ModePanic
+	// ModeWarn only reports the failure and lets the program continue.
+	ModeWarn
+	// ModeGoexit ends only the failing goroutine with runtime.Goexit, running
+	// its deferred calls, for libraries embedded in host applications that
+	// must not be taken down whole. A failure on the main goroutine leaves
+	// the program running until its other goroutines finish, after which it
+	// crashes as deadlocked.
+	ModeGoexit
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeExit:
+		return "exit"
+	case ModePanic:
+		return "panic"
+	case ModeWarn:
+		return "warn"
+	case ModeGoexit:
+		return "goexit"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// MarshalText encodes m as its name, so that it reads well in JSON.
+func (m Mode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes a name as returned by Mode.String.
+func (m *Mode) UnmarshalText(b []byte) error {
+	v, err := ParseMode(string(b))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// ParseMode parses the name of a mode as returned by Mode.String.
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "exit":
+		return ModeExit, nil
+	case "panic":
+		return ModePanic, nil
+	case "warn":
+		return ModeWarn, nil
+	case "goexit":
+		return ModeGoexit, nil
+	}
+	return ModeExit, fmt.Errorf("assert: unknown mode %q", s)
+}
+
+// SetMode sets what happens after a failure has been reported.
+func SetMode(m Mode) {
+	updateConfig(func(c *config) { c.mode = m })
+}
+
+// CurrentMode returns the mode set by SetMode.
+func CurrentMode() Mode {
+	return loadConfig().mode
+}
+
+// IncludeStack controls whether reports end with the failing goroutine's
+// stack. Stacks are included by default.
+func IncludeStack(on bool) {
+	updateConfig(func(c *config) { c.stack = on })
+}
+
+// SetExitFunc replaces the function ModeExit uses to terminate the process,
+// which is os.Exit by default. It lets crash paths be unit tested; if fn
+// returns, the failed assertion returns to its caller. A nil fn restores
+// os.Exit.
+func SetExitFunc(fn func(code int)) {
+	if fn == nil {
+		fn = os.Exit
+	}
+	updateConfig(func(c *config) { c.exit = fn })
+}
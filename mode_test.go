@@ -0,0 +1,52 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"io"
+	"testing"
+)
+
+// fails reports whether fn fails an assertion, with failures panicking and
+// their reports discarded.
+func fails(t *testing.T, fn func()) (failed bool) {
+	t.Helper()
+	mode, out := CurrentMode(), loadConfig().writer
+	SetMode(ModePanic)
+	ToWriter(io.Discard)
+	defer func() {
+		SetMode(mode)
+		ToWriter(out)
+		if e := recover(); e != nil {
+			if _, ok := e.(*AssertionError); !ok {
+				panic(e)
+			}
+			failed = true
+		}
+	}()
+	fn()
+	return false
+}
+
+func TestSetExitFunc(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	codes := []int{}
+	SetExitFunc(func(code int) { codes = append(codes, code) })
+	SetMode(ModeExit)
+	ToWriter(io.Discard)
+
+	Assert(true, "passes")
+	Assert(false, "fails")
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Fatalf("exit codes = %v, want [1]", codes)
+	}
+	NoError(io.EOF, "fails")
+	if len(codes) != 2 {
+		t.Fatalf("exit codes = %v, want two", codes)
+	}
+
+	SetExitFunc(nil)
+	if loadConfig().exit == nil {
+		t.Error("SetExitFunc(nil) left no exit function")
+	}
+}
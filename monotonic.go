@@ -0,0 +1,51 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"cmp"
+	"sync"
+)
+
+// Monotonic tracks a sequence that must never go backwards, such as log
+// sequence numbers or a replication clock. The zero value accepts any first
+// value and requires each later one to be at least the previous; it is safe
+// for concurrent use.
+//
+//	var applied assert.Monotonic[uint64]
+//
+//	applied.Observe(entry.LSN, "WAL applied out of order", "segment", seg)
+type Monotonic[T cmp.Ordered] struct {
+	// Strict requires each value to be greater than the previous one,
+	// rejecting repeats.
+	Strict bool
+
+	mu   sync.Mutex
+	last T
+	n    uint64
+}
+
+// Observe asserts that v does not go backwards from the previous value. The
+// report includes both values and how many values were observed. After a
+// failure the sequence continues from v, so one regression fails once.
+func (m *Monotonic[T]) Observe(v T, msg string, data ...any) {
+	m.mu.Lock()
+	prev, n := m.last, m.n
+	m.last = v
+	m.n++
+	m.mu.Unlock()
+
+	c := cmp.Compare(v, prev)
+	if failed := n > 0 && (c < 0 || m.Strict && c == 0); failed {
+		runAssert(msg, append(data, "monotonic.prev", prev, "monotonic.value", v, "monotonic.count", n+1)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// Last returns the last observed value and whether there was one.
+func (m *Monotonic[T]) Last() (T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last, m.n > 0
+}
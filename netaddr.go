@@ -0,0 +1,94 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ValidHostPort asserts that s is a "host:port" address as accepted by
+// net.Dial and net.Listen: an IP address, a host name or nothing, and a
+// numeric port. Host names follow RFC 1123, so labels with '_', such as
+// those of SRV records, are rejected. The report includes the quoted input
+// and what is wrong with it, instead of the opaque dial error it would cause
+// later.
+func ValidHostPort(s string, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	if err := checkHostPort(s); err != nil {
+		runAssert(msg, append(data, "input", strconv.Quote(s), "error", err)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// ValidIP asserts that s is an IPv4 or IPv6 address.
+func ValidIP(s string, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	if _, err := netip.ParseAddr(s); err != nil {
+		runAssert(msg, append(data, "input", strconv.Quote(s), "error", err)...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// PortInRange asserts that lo <= port <= hi, for example 1024 to 65535 for
+// ports that must not need privileges.
+func PortInRange(port, lo, hi int, msg string, data ...any) {
+	if port < lo || port > hi {
+		runAssert(msg, append(data, "port", port, "range", fmt.Sprintf("[%d, %d]", lo, hi))...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+func checkHostPort(s string) error {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return err
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("port %q is not a number between 0 and 65535", port)
+	}
+	if host == "" {
+		return nil
+	}
+	if strings.Contains(s, "[") {
+		if _, err := netip.ParseAddr(host); err != nil {
+			return fmt.Errorf("bracketed host %q is not an IP address", host)
+		}
+		return nil
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return nil
+	}
+	return checkHostname(host)
+}
+
+// checkHostname checks host against the host name rules of RFC 1123.
+func checkHostname(host string) error {
+	if len(host) > 253 {
+		return fmt.Errorf("host name is %d bytes long, more than 253", len(host))
+	}
+	for label := range strings.SplitSeq(strings.TrimSuffix(host, "."), ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("host name label %q must be 1 to 63 bytes", label)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("host name label %q starts or ends with '-'", label)
+		}
+		for _, c := range label {
+			if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+				return fmt.Errorf("host name label %q contains %q", label, c)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+//go:build !tinygo
+
+package assert
+
+import "testing"
+
+func TestCheckHostPort(t *testing.T) {
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		{"example.com:80", true},
+		{"db-1.internal.:5432", true},
+		{":8080", true},
+		{"[::1]:443", true},
+		{"127.0.0.1:65535", true},
+		{"example.com:65536", false},
+		{"example.com", false},
+		{"_srv.example.com:80", false},
+		{"my_host:80", false},
+		{"-bad.example.com:80", false},
+		{"[example.com]:80", false},
+	}
+	for _, tt := range tests {
+		if err := checkHostPort(tt.in); (err == nil) != tt.ok {
+			t.Errorf("checkHostPort(%q) = %v, want ok %v", tt.in, err, tt.ok)
+		}
+	}
+}
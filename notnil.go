@@ -0,0 +1,62 @@
+//go:build !tinygo
+
+package assert
+
+import "context"
+
+// NotNilPtr asserts that p is not nil. It is the reflection-free
+// counterpart of NotNil for pointers, cheap enough for per-item loops.
+func NotNilPtr[T any](p *T, msg string, data ...any) {
+	if p == nil || tracking.Load() {
+		evaluated(p == nil, msg, data)
+	}
+}
+
+// NotNilMap asserts that m is not a nil map.
+func NotNilMap[K comparable, V any](m map[K]V, msg string, data ...any) {
+	if m == nil || tracking.Load() {
+		evaluated(m == nil, msg, data)
+	}
+}
+
+// NotNilSlice asserts that s is not a nil slice. An empty, non-nil slice
+// passes.
+func NotNilSlice[T any](s []T, msg string, data ...any) {
+	if s == nil || tracking.Load() {
+		evaluated(s == nil, msg, data)
+	}
+}
+
+// NotNilChan asserts that c is not a nil channel. It accepts bidirectional
+// and receive-only channels; NotNilSendChan takes send-only ones.
+func NotNilChan[T any](c <-chan T, msg string, data ...any) {
+	if c == nil || tracking.Load() {
+		evaluated(c == nil, msg, data)
+	}
+}
+
+// NotNilSendChan asserts that the send-only channel c is not nil.
+func NotNilSendChan[T any](c chan<- T, msg string, data ...any) {
+	if c == nil || tracking.Load() {
+		evaluated(c == nil, msg, data)
+	}
+}
+
+// FuncType is the constraint of NotNilFunc: the func shapes callbacks most
+// often have, and types defined from them. Go has no constraint matching
+// every func type; check other funcs with Assert(fn != nil, ...), which is
+// just as cheap.
+type FuncType interface {
+	~func() | ~func() error | ~func() bool |
+		~func(error) | ~func(context.Context) | ~func(context.Context) error |
+		~func(any) | ~func(any) error | ~func(string) | ~func(string) error |
+		~func(int) | ~func(int) error | ~func([]byte) | ~func([]byte) error
+}
+
+// NotNilFunc asserts that fn is not a nil func. Values that are not funcs
+// do not compile.
+func NotNilFunc[F FuncType](fn F, msg string, data ...any) {
+	if fn == nil || tracking.Load() {
+		evaluated(fn == nil, msg, data)
+	}
+}
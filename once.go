@@ -0,0 +1,49 @@
+//go:build !tinygo
+
+package assert
+
+import "sync/atomic"
+
+var reportOnce atomic.Bool
+var onceSites siteState[siteKey, onceSite]
+
+// onceSite tracks a call site under ReportOnce. After the first failure the
+// flag is only read, and repeats go to a sharded counter, so a site failing
+// in many goroutines at once does not contend on one cache line.
+type onceSite struct {
+	reported   atomic.Bool
+	suppressed shardedCounter
+}
+
+// ReportOnce makes each call site report only its first failure while in
+// ModeWarn. Later failures from the same site are counted, not reported, so
+// a broken invariant inside a loop cannot flood the output.
+func ReportOnce(on bool) {
+	reportOnce.Store(on)
+}
+
+// Suppressed returns the number of failures that ReportOnce suppressed,
+// keyed by "file:line" of the call site.
+func Suppressed() map[string]uint64 {
+	counts := map[string]uint64{}
+	onceSites.each(func(k siteKey, s *onceSite) {
+		if n := s.suppressed.Load(); n > 0 {
+			counts[k.String()] = n
+		}
+	})
+	return counts
+}
+
+// suppressRepeat counts a failure at key and reports whether it should be
+// suppressed because the site has already reported.
+func suppressRepeat(m Mode, key siteKey) bool {
+	if !reportOnce.Load() || m != ModeWarn {
+		return false
+	}
+	s := onceSites.get(key)
+	if !s.reported.Load() && s.reported.CompareAndSwap(false, true) {
+		return false
+	}
+	s.suppressed.Add(1)
+	return true
+}
@@ -0,0 +1,78 @@
+//go:build !tinygo
+
+package assert
+
+// Integer is the set of integer types the overflow-checked helpers accept.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// AddNoOverflow returns a + b, asserting that the sum does not wrap around.
+// If the assertion does not stop the program, the wrapped sum is returned.
+//
+//	total = assert.AddNoOverflow(total, amount, "balance overflow", "account", id)
+func AddNoOverflow[T Integer](a, b T, msg string, data ...any) T {
+	s := a + b
+	var overflow bool
+	if signed[T]() {
+		overflow = b > 0 && s < a || b < 0 && s > a
+	} else {
+		overflow = s < a
+	}
+	if overflow || tracking.Load() {
+		overflowChecked(overflow, "+", a, b, s, msg, data)
+	}
+	return s
+}
+
+// SubNoOverflow returns a - b, asserting that the difference does not wrap
+// around, including unsigned differences going below zero.
+func SubNoOverflow[T Integer](a, b T, msg string, data ...any) T {
+	d := a - b
+	var overflow bool
+	if signed[T]() {
+		overflow = b > 0 && d > a || b < 0 && d < a
+	} else {
+		overflow = b > a
+	}
+	if overflow || tracking.Load() {
+		overflowChecked(overflow, "-", a, b, d, msg, data)
+	}
+	return d
+}
+
+// MulNoOverflow returns a * b, asserting that the product does not wrap
+// around.
+func MulNoOverflow[T Integer](a, b T, msg string, data ...any) T {
+	p := a * b
+	overflow := a != 0 && p/a != b
+	if signed[T]() && a+1 == 0 && b < 0 && -b == b {
+		// The most negative value times -1 wraps to itself, which the
+		// division above cannot detect.
+		overflow = true
+	}
+	if overflow || tracking.Load() {
+		overflowChecked(overflow, "*", a, b, p, msg, data)
+	}
+	return p
+}
+
+// signed reports whether T is a signed integer type.
+func signed[T Integer]() bool {
+	var zero T
+	return ^zero < 0
+}
+
+func overflowChecked[T Integer](failed bool, op string, a, b, result T, msg string, data []any) {
+	if !failed {
+		passed(msg, data)
+		return
+	}
+	runAssert(msg, append(data,
+		"overflow.op", "a "+op+" b",
+		"overflow.a", a,
+		"overflow.b", b,
+		"overflow.result", result,
+	)...)
+}
@@ -0,0 +1,77 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAddNoOverflow(t *testing.T) {
+	tests := []overflowTest{
+		{"int", func() any { return AddNoOverflow(2, 3, "") }, 5, false},
+		{"int negative", func() any { return AddNoOverflow(-2, -3, "") }, -5, false},
+		{"int max", func() any { return AddNoOverflow(math.MaxInt, 1, "") }, nil, true},
+		{"int min", func() any { return AddNoOverflow(math.MinInt, -1, "") }, nil, true},
+		{"int8 edge", func() any { return AddNoOverflow[int8](100, 27, "") }, int8(127), false},
+		{"int8", func() any { return AddNoOverflow[int8](100, 28, "") }, nil, true},
+		{"uint8 edge", func() any { return AddNoOverflow[uint8](200, 55, "") }, uint8(255), false},
+		{"uint8", func() any { return AddNoOverflow[uint8](200, 56, "") }, nil, true},
+		{"uint64", func() any { return AddNoOverflow[uint64](math.MaxUint64, 1, "") }, nil, true},
+		{"named", func() any { return AddNoOverflow(ticks(math.MaxInt64), 1, "") }, nil, true},
+	}
+	runOverflowTests(t, tests)
+}
+
+func TestSubNoOverflow(t *testing.T) {
+	tests := []overflowTest{
+		{"int", func() any { return SubNoOverflow(2, 3, "") }, -1, false},
+		{"int min", func() any { return SubNoOverflow(math.MinInt, 1, "") }, nil, true},
+		{"int max", func() any { return SubNoOverflow(math.MaxInt, -1, "") }, nil, true},
+		{"int8 edge", func() any { return SubNoOverflow[int8](-100, 28, "") }, int8(-128), false},
+		{"uint zero", func() any { return SubNoOverflow[uint](3, 3, "") }, uint(0), false},
+		{"uint below zero", func() any { return SubNoOverflow[uint](2, 3, "") }, nil, true},
+		{"uintptr", func() any { return SubNoOverflow[uintptr](0, 1, "") }, nil, true},
+	}
+	runOverflowTests(t, tests)
+}
+
+func TestMulNoOverflow(t *testing.T) {
+	tests := []overflowTest{
+		{"int", func() any { return MulNoOverflow(-4, 5, "") }, -20, false},
+		{"zero", func() any { return MulNoOverflow(0, math.MaxInt, "") }, 0, false},
+		{"int max", func() any { return MulNoOverflow(math.MaxInt/2+1, 2, "") }, nil, true},
+		{"int8 edge", func() any { return MulNoOverflow[int8](-64, 2, "") }, int8(-128), false},
+		{"int8", func() any { return MulNoOverflow[int8](64, 2, "") }, nil, true},
+		{"min times minus one", func() any { return MulNoOverflow[int8](-1, math.MinInt8, "") }, nil, true},
+		{"minus one times min", func() any { return MulNoOverflow[int8](math.MinInt8, -1, "") }, nil, true},
+		{"uint32", func() any { return MulNoOverflow[uint32](1<<16, 1<<16, "") }, nil, true},
+		{"uint32 edge", func() any { return MulNoOverflow[uint32](1<<16, 1<<15, "") }, uint32(1 << 31), false},
+	}
+	runOverflowTests(t, tests)
+}
+
+// ticks is a named integer type.
+type ticks int64
+
+type overflowTest struct {
+	name     string
+	fn       func() any // returns the helper's result
+	want     any
+	overflow bool
+}
+
+func runOverflowTests(t *testing.T, tests []overflowTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got any
+			if failed := fails(t, func() { got = tt.fn() }); failed != tt.overflow {
+				t.Fatalf("failed = %v, want %v", failed, tt.overflow)
+			}
+			if !tt.overflow && got != tt.want {
+				t.Errorf("result = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// PathWithin asserts that candidate, after resolving "..", symlinks and, if
+// it is relative, joining it to root, stays inside root. It is a defense in
+// depth check for paths built from external input:
+//
+//	p := filepath.Join(uploadDir, name)
+//	assert.PathWithin(uploadDir, p, "upload escapes its directory", "name", name)
+//
+// The candidate need not exist; the part of it that does is resolved, and a
+// dangling symlink counts as its target. The report includes both paths as
+// given and as resolved.
+func PathWithin(root, candidate string, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	full := candidate
+	if !filepath.IsAbs(full) {
+		// Not filepath.Join, which would apply ".." before the symlinks
+		// it follows are resolved.
+		full = root + string(filepath.Separator) + full
+	}
+	r, rerr := resolvePath(root)
+	c, cerr := resolvePath(full)
+	if err := errors.Join(rerr, cerr); err != nil || !within(r, c) {
+		failed := append(data,
+			"path.root", root,
+			"path.candidate", candidate,
+			"path.root_resolved", r,
+			"path.candidate_resolved", c,
+		)
+		if err != nil {
+			failed = append(failed, "error", err)
+		}
+		runAssert(msg, failed...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// within reports whether the clean, absolute path p is root or below it.
+func within(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// maxPathLinks bounds the symlinks resolvePath follows, as ELOOP does.
+const maxPathLinks = 255
+
+// resolvePath returns the absolute form of p with its symlinks evaluated.
+// It walks p one element at a time, resolving each symlink before applying
+// any ".." that follows it, as the kernel does. A dangling symlink resolves
+// to its target; elements that do not exist are joined as they are.
+func resolvePath(p string) (string, error) {
+	if !filepath.IsAbs(p) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return p, err
+		}
+		p = wd + string(filepath.Separator) + p
+	}
+	vol := filepath.VolumeName(p)
+	resolved, todo := vol+string(filepath.Separator), p[len(vol):]
+	for links := 0; todo != ""; {
+		var name string
+		name, todo = splitFirst(todo)
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+		next := filepath.Join(resolved, name)
+		fi, err := os.Lstat(next)
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+			resolved = next
+			continue
+		}
+		if err != nil {
+			return next, err
+		}
+		if fi.Mode()&fs.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		if links++; links > maxPathLinks {
+			return next, fmt.Errorf("more than %d symlinks", maxPathLinks)
+		}
+		target, err := os.Readlink(next)
+		if err != nil {
+			return next, err
+		}
+		if filepath.IsAbs(target) {
+			vol := filepath.VolumeName(target)
+			resolved, target = vol+string(filepath.Separator), target[len(vol):]
+		}
+		todo = target + string(filepath.Separator) + todo
+	}
+	return resolved, nil
+}
+
+// splitFirst splits the first element off the path p.
+func splitFirst(p string) (first, rest string) {
+	for i := 0; i < len(p); i++ {
+		if os.IsPathSeparator(p[i]) {
+			return p[:i], p[i+1:]
+		}
+	}
+	return p, ""
+}
@@ -0,0 +1,90 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathWithin(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{filepath.Join(root, "sub"), outside} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"out":     outside,
+		"dangle":  filepath.Join(outside, "missing"),
+		"rel":     filepath.Join("..", "outside"),
+		"inner":   "sub",
+		"loop":    "loop",
+		"chained": "out",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Skipf("cannot create symlinks: %v", err)
+		}
+	}
+
+	tests := []struct {
+		candidate string
+		escapes   bool
+	}{
+		{"file", false},
+		{"sub/file", false},
+		{"sub/../file", false},
+		{"missing/dir/file", false},
+		{"inner/file", false},
+		{"inner/../file", false},
+		{"missing/../inner/file", false},
+		{".", false},
+		{"..", true},
+		{"sub/../../outside", true},
+		{"missing/../../outside", true},
+		{"out", true},
+		{"out/file", true},
+		{"chained/file", true},
+		{"rel/file", true},
+		{"out/../root/file", false},
+		// ".." after a symlink applies to its target, not the link.
+		{"out/../x", true},
+		{"rel/../x", true},
+		// A dangling symlink counts as its target.
+		{"dangle", true},
+		{"dangle/file", true},
+		{"loop/file", true},
+		{filepath.Join(outside, "file"), true},
+		{filepath.Join(root, "sub", "file"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.candidate, func(t *testing.T) {
+			failed := fails(t, func() { PathWithin(root, tt.candidate, "escape") })
+			if failed != tt.escapes {
+				t.Errorf("PathWithin(root, %q) failed = %v, want %v", tt.candidate, failed, tt.escapes)
+			}
+		})
+	}
+}
+
+func TestPathWithinLinkedRoot(t *testing.T) {
+	base := t.TempDir()
+	real := filepath.Join(base, "real")
+	if err := os.Mkdir(real, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Join(base, "root")
+	if err := os.Symlink(real, root); err != nil {
+		t.Skipf("cannot create symlinks: %v", err)
+	}
+	if fails(t, func() { PathWithin(root, filepath.Join(real, "file"), "escape") }) {
+		t.Error("a path below the target of a linked root failed")
+	}
+	if !fails(t, func() { PathWithin(root, filepath.Join(root, "..", "file"), "escape") }) {
+		t.Error("a path beside the target of a linked root passed")
+	}
+}
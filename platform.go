@@ -0,0 +1,14 @@
+//go:build !tinygo && !(js && wasm)
+
+package assert
+
+import (
+	"io"
+	"os"
+)
+
+const defaultMode = ModeExit
+
+func defaultOutput() io.Writer {
+	return os.Stderr
+}
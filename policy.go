@@ -0,0 +1,273 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Action is what a policy Rule does with a failure.
+type Action int
+
+const (
+	// ActionDefault leaves the failure to the mode and minimum severity.
+	ActionDefault Action = iota
+	// ActionExit handles the failure as in ModeExit.
+	ActionExit
+	// ActionPanic handles the failure as in ModePanic.
+	ActionPanic
+	// ActionWarn handles the failure as in ModeWarn.
+	ActionWarn
+	// ActionSample reports a fraction, Rule.Rate, of the failures as in
+	// ModeWarn and ignores the rest.
+	ActionSample
+	// ActionIgnore ignores the failure, as if its area were disabled.
+	ActionIgnore
+	// ActionGoexit handles the failure as in ModeGoexit.
+	ActionGoexit
+)
+
+func (a Action) String() string {
+	switch a {
+	case ActionDefault:
+		return "default"
+	case ActionExit:
+		return "exit"
+	case ActionPanic:
+		return "panic"
+	case ActionWarn:
+		return "warn"
+	case ActionSample:
+		return "sample"
+	case ActionIgnore:
+		return "ignore"
+	case ActionGoexit:
+		return "goexit"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// ParseAction parses the name of an action as returned by Action.String.
+func ParseAction(s string) (Action, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "default":
+		return ActionDefault, nil
+	case "exit":
+		return ActionExit, nil
+	case "panic":
+		return ActionPanic, nil
+	case "warn":
+		return ActionWarn, nil
+	case "sample":
+		return ActionSample, nil
+	case "ignore":
+		return ActionIgnore, nil
+	case "goexit":
+		return ActionGoexit, nil
+	}
+	return ActionDefault, fmt.Errorf("assert: unknown action %q", s)
+}
+
+// AnySeverity makes a Rule match failures of every severity.
+const AnySeverity Severity = -1
+
+// Rule maps failures in an area with a severity to an action.
+type Rule struct {
+	// Area is matched like the area rules of EnableArea: a rule on
+	// "storage" also applies to "storage.compaction". An empty Area
+	// matches every failure.
+	Area     string
+	Severity Severity // or AnySeverity
+	Action   Action
+	Rate     float64 // fraction reported by ActionSample
+}
+
+// String formats r as a line of a policy file.
+func (r Rule) String() string {
+	area, severity := r.Area, "*"
+	if area == "" {
+		area = "*"
+	}
+	if r.Severity != AnySeverity {
+		severity = r.Severity.String()
+	}
+	s := area + " " + severity + " " + r.Action.String()
+	if r.Action == ActionSample {
+		s += " " + strconv.FormatFloat(r.Rate, 'g', -1, 64)
+	}
+	return s
+}
+
+// MarshalText encodes r as a line of a policy file.
+func (r Rule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes a line of a policy file.
+func (r *Rule) UnmarshalText(b []byte) error {
+	v, err := parseRule(strings.Fields(string(b)))
+	if err != nil {
+		return fmt.Errorf("assert: rule %q: %w", b, err)
+	}
+	*r = v
+	return nil
+}
+
+// SetPolicy replaces the policy, the rules that decide per failure what
+// happens to it, so large code bases can enforce assertions gradually
+// without touching call sites:
+//
+//	assert.SetPolicy(
+//		assert.Rule{Area: "storage", Severity: assert.AnySeverity, Action: assert.ActionExit},
+//		assert.Rule{Area: "ui", Severity: assert.SeverityError, Action: assert.ActionSample, Rate: 0.01},
+//	)
+//
+// The rule on the most specific area that matches a failure wins, and among
+// those, one for the failure's exact severity beats one for AnySeverity;
+// if rules still tie, the last one wins. Failures no rule matches, or whose
+// rule is ActionDefault, follow the mode and minimum severity. Calling
+// SetPolicy without rules removes the policy. Disabled areas stay disabled
+// whatever the policy says. A sample rate outside [0, 1] is an error, and
+// the policy is left as it was.
+func SetPolicy(rules ...Rule) error {
+	for _, r := range rules {
+		if r.Action == ActionSample {
+			if err := checkRate(r.Rate); err != nil {
+				return fmt.Errorf("assert: rule %q: %w", r, err)
+			}
+		}
+	}
+	rules = append([]Rule(nil), rules...)
+	updateConfig(func(c *config) { c.policy = rules })
+	return nil
+}
+
+// checkRate returns an error unless rate, a sample rate, is in [0, 1].
+func checkRate(rate float64) error {
+	if !(rate >= 0 && rate <= 1) {
+		return fmt.Errorf("rate %v outside [0, 1]", rate)
+	}
+	return nil
+}
+
+// Policy returns the rules set with SetPolicy or LoadPolicy.
+func Policy() []Rule {
+	return append([]Rule(nil), loadConfig().policy...)
+}
+
+// LoadPolicy reads a policy file and installs it with SetPolicy. Each line
+// holds a rule as "area severity action [rate]", where area may be "*" for
+// every area and severity "*" for every severity. Blank lines and lines
+// starting with # are ignored:
+//
+//	# area            severity  action  rate
+//	*                 debug     ignore
+//	storage           *         exit
+//	ui                error     sample  0.01
+func LoadPolicy(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rules, err := ParsePolicy(f)
+	if err != nil {
+		return fmt.Errorf("assert: %s: %w", path, err)
+	}
+	return SetPolicy(rules...)
+}
+
+// ParsePolicy parses rules in the format read by LoadPolicy.
+func ParsePolicy(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := parseRule(strings.Fields(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, sc.Err()
+}
+
+func parseRule(fields []string) (Rule, error) {
+	if len(fields) < 3 || len(fields) > 4 {
+		return Rule{}, fmt.Errorf("want \"area severity action [rate]\", got %d fields", len(fields))
+	}
+	var rule Rule
+	if fields[0] != "*" {
+		rule.Area = fields[0]
+	}
+	rule.Severity = AnySeverity
+	if fields[1] != "*" {
+		s, err := ParseSeverity(fields[1])
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Severity = s
+	}
+	a, err := ParseAction(fields[2])
+	if err != nil {
+		return Rule{}, err
+	}
+	rule.Action = a
+	if len(fields) == 4 {
+		if a != ActionSample {
+			return Rule{}, fmt.Errorf("rate given for action %s", a)
+		}
+		if rule.Rate, err = strconv.ParseFloat(fields[3], 64); err != nil {
+			return Rule{}, fmt.Errorf("bad rate: %w", err)
+		}
+		if err := checkRate(rule.Rate); err != nil {
+			return Rule{}, err
+		}
+	} else if a == ActionSample {
+		return Rule{}, fmt.Errorf("action sample needs a rate")
+	}
+	return rule, nil
+}
+
+// policyAction returns the action of the rule that applies to a failure in
+// area with severity s.
+func (c *config) policyAction(area string, s Severity) (Action, float64) {
+	best, bestDepth, bestExact := -1, -1, false
+	for i, rule := range c.policy {
+		if rule.Severity != AnySeverity && rule.Severity != s {
+			continue
+		}
+		depth := areaDepth(rule.Area, area)
+		if depth < 0 {
+			continue
+		}
+		exact := rule.Severity != AnySeverity
+		if depth > bestDepth || depth == bestDepth && (exact || !bestExact) {
+			best, bestDepth, bestExact = i, depth, exact
+		}
+	}
+	if best < 0 {
+		return ActionDefault, 0
+	}
+	return c.policy[best].Action, c.policy[best].Rate
+}
+
+// areaDepth returns how specific rule is as a match for area: the number of
+// its dot separated parts, or -1 if it does not match.
+func areaDepth(rule, area string) int {
+	if rule == "" {
+		return 0
+	}
+	if area != rule && !strings.HasPrefix(area, rule+".") {
+		return -1
+	}
+	return strings.Count(rule, ".") + 1
+}
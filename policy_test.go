@@ -0,0 +1,135 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPolicyPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []Rule
+		area     string
+		severity Severity
+		want     Action
+	}{
+		{"no rules", nil, "storage", SeverityFatal, ActionDefault},
+		{"no match", []Rule{{"ui", AnySeverity, ActionIgnore, 0}}, "storage", SeverityFatal, ActionDefault},
+		{"empty area", []Rule{{"", AnySeverity, ActionWarn, 0}}, "storage", SeverityFatal, ActionWarn},
+		{"prefix", []Rule{{"storage", AnySeverity, ActionExit, 0}}, "storage.wal", SeverityWarn, ActionExit},
+		{"not a part prefix", []Rule{{"stor", AnySeverity, ActionExit, 0}}, "storage", SeverityWarn, ActionDefault},
+		{"other severity", []Rule{{"storage", SeverityDebug, ActionIgnore, 0}}, "storage", SeverityError, ActionDefault},
+		{
+			"specific area wins",
+			[]Rule{{"storage.wal", AnySeverity, ActionPanic, 0}, {"storage", SeverityError, ActionIgnore, 0}},
+			"storage.wal.sync", SeverityError, ActionPanic,
+		},
+		{
+			"exact severity listed first",
+			[]Rule{{"storage", SeverityError, ActionPanic, 0}, {"storage", AnySeverity, ActionIgnore, 0}},
+			"storage", SeverityError, ActionPanic,
+		},
+		{
+			"last wins",
+			[]Rule{{"storage", AnySeverity, ActionPanic, 0}, {"storage", AnySeverity, ActionWarn, 0}},
+			"storage", SeverityError, ActionWarn,
+		},
+		{
+			"exact severity listed last",
+			[]Rule{{"storage", AnySeverity, ActionWarn, 0}, {"storage", SeverityError, ActionPanic, 0}},
+			"storage", SeverityError, ActionPanic,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &config{policy: tt.rules}
+			if got, _ := c.policyAction(tt.area, tt.severity); got != tt.want {
+				t.Errorf("policyAction(%q, %v) = %v, want %v", tt.area, tt.severity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModeFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		c        config
+		severity Severity
+		want     Mode
+		report   bool
+	}{
+		{"mode", config{mode: ModePanic}, SeverityFatal, ModePanic, true},
+		{"below min severity", config{mode: ModePanic, minSeverity: SeverityError}, SeverityWarn, ModeWarn, true},
+		{"policy over min severity", config{mode: ModeWarn, minSeverity: SeverityFatal, policy: []Rule{{"", AnySeverity, ActionExit, 0}}}, SeverityDebug, ModeExit, true},
+		{"default rule", config{mode: ModePanic, policy: []Rule{{"", AnySeverity, ActionDefault, 0}}}, SeverityFatal, ModePanic, true},
+		{"ignore", config{mode: ModePanic, policy: []Rule{{"", AnySeverity, ActionIgnore, 0}}}, SeverityFatal, ModeWarn, false},
+		{"sample none", config{policy: []Rule{{"", AnySeverity, ActionSample, 0}}}, SeverityFatal, ModeWarn, false},
+		{"sample all", config{policy: []Rule{{"", AnySeverity, ActionSample, 1}}}, SeverityFatal, ModeWarn, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ok := tt.c.modeFor("storage", tt.severity)
+			if m != tt.want || ok != tt.report {
+				t.Errorf("modeFor = %v, %v, want %v, %v", m, ok, tt.want, tt.report)
+			}
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		line string
+		want Rule
+		err  string
+	}{
+		{"* * exit", Rule{"", AnySeverity, ActionExit, 0}, ""},
+		{"ui error sample 0.01", Rule{"ui", SeverityError, ActionSample, 0.01}, ""},
+		{"storage warn ignore", Rule{"storage", SeverityWarn, ActionIgnore, 0}, ""},
+		{"ui error sample", Rule{}, "needs a rate"},
+		{"ui error sample 1.5", Rule{}, "outside [0, 1]"},
+		{"ui error sample -0.1", Rule{}, "outside [0, 1]"},
+		{"ui error sample NaN", Rule{}, "outside [0, 1]"},
+		{"ui error exit 0.5", Rule{}, "rate given"},
+		{"ui loud exit", Rule{}, "loud"},
+		{"ui error", Rule{}, "fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			rules, err := ParsePolicy(strings.NewReader("# comment\n\n" + tt.line + "\n"))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rules) != 1 || rules[0] != tt.want {
+				t.Errorf("rules = %v, want [%v]", rules, tt.want)
+			}
+			if s := rules[0].String(); s != tt.line {
+				t.Errorf("String = %q, want %q", s, tt.line)
+			}
+		})
+	}
+}
+
+func TestSetPolicyRate(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	keep := Rule{"ui", AnySeverity, ActionIgnore, 0}
+	if err := SetPolicy(keep); err != nil {
+		t.Fatal(err)
+	}
+	for _, rate := range []float64{-1, 2, math.NaN()} {
+		if err := SetPolicy(Rule{"", AnySeverity, ActionSample, rate}); err == nil {
+			t.Errorf("SetPolicy accepted rate %v", rate)
+		}
+	}
+	if p := Policy(); len(p) != 1 || p[0] != keep {
+		t.Errorf("policy = %v after rejected rules, want it unchanged", p)
+	}
+}
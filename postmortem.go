@@ -0,0 +1,183 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+// ReportVersion is the version of the post-mortem file format written by
+// this package. LoadReport reads files of this and earlier versions.
+const ReportVersion = 1
+
+// postMortem is the encoding of a post-mortem file: the Failure, with its
+// Process set, and the format version.
+type postMortem struct {
+	Version int
+	Failure Failure
+}
+
+func (pm postMortem) MarshalJSON() ([]byte, error) {
+	return pm.Failure.marshalJSON(pm.Version)
+}
+
+// UnmarshalJSON decodes a post-mortem file, or a FormatJSON line, for which
+// Version is 0.
+func (pm *postMortem) UnmarshalJSON(b []byte) error {
+	v, err := pm.Failure.unmarshalJSON(b)
+	pm.Version = v
+	return err
+}
+
+// IncludePostMortem controls whether fatal failures write their full
+// Failure, including the details of the process, next to the text report in
+// the crash directory set with SetCrashDir, as a ".json" file that LoadReport
+// reads back. It is off by default.
+func IncludePostMortem(on bool) {
+	updateConfig(func(c *config) { c.postMortem = on })
+}
+
+// LoadReport reads a post-mortem file written by a fatal failure, gzip
+// compressed or not. Encrypted files are decrypted first and passed to
+// ReadReport.
+func LoadReport(path string) (Failure, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Failure{}, err
+	}
+	defer f.Close()
+	rep, err := ReadReport(f)
+	if err != nil {
+		return Failure{}, fmt.Errorf("assert: reading report %s: %w", path, err)
+	}
+	return rep, nil
+}
+
+// ReadReport reads a post-mortem file, gzip compressed or not, from r.
+func ReadReport(r io.Reader) (Failure, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Failure{}, err
+	}
+	if b, err = gunzip(b); err != nil {
+		return Failure{}, err
+	}
+	var pm postMortem
+	if err := json.Unmarshal(b, &pm); err != nil {
+		return Failure{}, err
+	}
+	if pm.Version < 1 || pm.Version > ReportVersion {
+		return Failure{}, fmt.Errorf("unsupported report version %d", pm.Version)
+	}
+	return pm.Failure, nil
+}
+
+// ReadFailures reads serialized failures from r and calls fn with each, in
+// whichever form they were written: FormatJSON lines, a FormatBinary stream
+// or post-mortem reports, gzip compressed or not. It returns the first error
+// from fn, or nil at the end of r.
+func ReadFailures(r io.Reader, fn func(Failure) error) error {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		br = bufio.NewReader(zr)
+	}
+	for {
+		b, err := br.Peek(1)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		br.ReadByte()
+	}
+	if b, _ := br.Peek(1); b[0] == '{' {
+		return readJSONFailures(br, fn)
+	}
+	dec := NewFailureDecoder(br)
+	for {
+		f, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONFailures(r io.Reader, fn func(Failure) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var pm postMortem
+		if err := dec.Decode(&pm); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if v := pm.Version; v < 0 || v > ReportVersion {
+			return fmt.Errorf("unsupported report version %d", v)
+		}
+		if err := fn(pm.Failure); err != nil {
+			return err
+		}
+	}
+}
+
+// writePostMortem writes r's Failure, with the details of the process, to
+// path as a crash file.
+func writePostMortem(c *config, path string, r *report) error {
+	host, _ := os.Hostname()
+	pm := postMortem{Version: ReportVersion, Failure: r.failure()}
+	pm.Failure.Process = &Process{
+		PID:       os.Getpid(),
+		Args:      c.reportedArgs(),
+		Hostname:  host,
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+	b, err := json.MarshalIndent(pm, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := c.createCrashFile(path, c.compressCrash)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// gunzip decompresses b if it starts with the gzip magic number and returns
+// it unchanged otherwise.
+func gunzip(b []byte) ([]byte, error) {
+	if len(b) < 2 || b[0] != 0x1f || b[1] != 0x8b {
+		return b, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(zr)
+}
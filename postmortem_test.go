@@ -0,0 +1,54 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+)
+
+func TestReadFailures(t *testing.T) {
+	r := testReport("k", 1)
+	var jsonLines, binary bytes.Buffer
+	r.renderJSON(&jsonLines)
+	r.renderJSON(&jsonLines)
+	r.renderBinary(&binary)
+	r.renderBinary(&binary)
+	pm, _ := json.Marshal(postMortem{Version: ReportVersion, Failure: r.failure()})
+	var zipped bytes.Buffer
+	zw := gzip.NewWriter(&zipped)
+	zw.Write(jsonLines.Bytes())
+	zw.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"json", jsonLines.Bytes(), 2},
+		{"binary", binary.Bytes(), 2},
+		{"post-mortem", pm, 1},
+		{"gzip", zipped.Bytes(), 2},
+		{"empty", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := 0
+			err := ReadFailures(bytes.NewReader(tt.data), func(f Failure) error {
+				n++
+				if f.Message != r.msg || f.Fingerprint != r.fingerprint {
+					t.Errorf("got %+v", f)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tt.want {
+				t.Errorf("read %d failures, want %d", n, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ProgressTracker asserts that a long-running loop advances towards a known
+// total. See Progress.
+type ProgressTracker struct {
+	name    string
+	total   int64
+	data    []any
+	started time.Time
+	done    atomic.Int64
+}
+
+// Progress starts tracking a loop of total units of work. Each Step must
+// advance it, and it must never pass total. While the tracker is active,
+// every failure report, from any assertion, includes its progress, rate and
+// estimated time to completion under "progress.<name>". Call Finish when the
+// loop ends.
+//
+//	p := assert.Progress("rebuild index", len(docs))
+//	defer p.Finish()
+//	for _, d := range docs {
+//		index(d)
+//		p.Step(1)
+//	}
+func Progress(name string, total int, data ...any) *ProgressTracker {
+	p := &ProgressTracker{name: name, total: int64(total), data: data, started: now()}
+	AddAssertData("progress."+name, p)
+	return p
+}
+
+// Step records n more units of work done. It fails if n is not positive or
+// the work done would exceed the total.
+func (p *ProgressTracker) Step(n int) {
+	done := p.done.Add(int64(n))
+	if n <= 0 || done > p.total {
+		p.stepFailed(n, done)
+	}
+}
+
+func (p *ProgressTracker) stepFailed(n int, done int64) {
+	msg := "progress exceeded total"
+	if n <= 0 {
+		msg = "progress not advancing"
+	}
+	runAssert(msg, append(p.data[:len(p.data):len(p.data)],
+		"progress", p.name,
+		"progress.step", n,
+		"progress.done", done,
+		"progress.total", p.total,
+	)...)
+}
+
+// Done returns the units of work done so far.
+func (p *ProgressTracker) Done() int {
+	return int(p.done.Load())
+}
+
+// Finish stops tracking, removing the progress from reports.
+func (p *ProgressTracker) Finish() {
+	RemoveAssertData("progress." + p.name)
+}
+
+// Dump describes the progress for failure reports.
+func (p *ProgressTracker) Dump() string {
+	done := p.done.Load()
+	elapsed := now().Sub(p.started)
+	s := fmt.Sprintf("%d/%d", done, p.total)
+	if p.total > 0 {
+		s += fmt.Sprintf(" (%.1f%%)", 100*float64(done)/float64(p.total))
+	}
+	s += fmt.Sprintf(", elapsed %s", elapsed.Round(time.Millisecond))
+	if done <= 0 || elapsed <= 0 {
+		return s
+	}
+	rate := float64(done) / elapsed.Seconds()
+	s += fmt.Sprintf(", %.1f/s", rate)
+	if remaining := p.total - done; remaining > 0 {
+		eta := time.Duration(float64(remaining) / rate * float64(time.Second))
+		s += fmt.Sprintf(", eta %s", eta.Round(time.Millisecond))
+	}
+	return s
+}
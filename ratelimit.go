@@ -0,0 +1,62 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket bounding how many reports are written in
+// ModeWarn. Reports that do not fit are counted and summarised in the next
+// report that does.
+type limiter struct {
+	mu         sync.Mutex
+	burst      float64
+	per        time.Duration
+	tokens     float64
+	last       time.Time
+	suppressed uint64
+}
+
+var reportLimiter limiter
+
+// SetRateLimit allows at most n reports per interval while in ModeWarn.
+// Failures over the limit are dropped, and the next report that is written
+// notes how many were suppressed. A zero n removes the limit.
+func SetRateLimit(n int, per time.Duration) {
+	reportLimiter.mu.Lock()
+	defer reportLimiter.mu.Unlock()
+
+	reportLimiter.burst = float64(n)
+	reportLimiter.per = per
+	reportLimiter.tokens = float64(n)
+	reportLimiter.last = now()
+}
+
+// allow takes a token, returning false if the failure must not be reported.
+// When it returns true, suppressed is the number of failures dropped since
+// the previous report.
+func (l *limiter) allow() (ok bool, suppressed uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.burst <= 0 || l.per <= 0 {
+		return true, 0
+	}
+
+	t := now()
+	l.tokens += t.Sub(l.last).Seconds() / l.per.Seconds() * l.burst
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = t
+
+	if l.tokens < 1 {
+		l.suppressed++
+		return false, 0
+	}
+	l.tokens--
+	suppressed, l.suppressed = l.suppressed, 0
+	return true, suppressed
+}
@@ -0,0 +1,169 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// report is a single assertion failure as it is written out.
+type report struct {
+	msg         string
+	area        string
+	severity    Severity
+	id          string // stable ID set with IDKey
+	time        time.Time
+	site        siteKey // call site, set for reported failures
+	function    string  // function containing the call site
+	mode        Mode    // how the failure is handled
+	fingerprint string
+	expr        string // source of the failed condition, if registered
+	args        []any  // caller supplied key/value pairs
+	labels      []any  // pprof labels of the failing goroutine
+	dumps       []any  // assert data key/value pairs
+	changes     []any  // assert data changed since the last Snapshot
+	system      []any  // process and system context, see systemPairs
+	crumbs      []breadcrumb
+	logs        []logRecord // recent warnings and errors, see TapSlog
+	stack       []byte
+	dropped     uint64 // failures suppressed by the rate limit before this one
+
+	siteEvals    uint64 // evaluations of the site so far, if tracking
+	siteFailures uint64 // failures of the site so far, if tracking
+
+	simSeed   uint64 // seed of the running simulation
+	simulated bool
+}
+
+func newReport(msg string, args []any) *report {
+	// Copy the caller's data so that the variadic slice does not escape and
+	// passing assertions stay allocation free.
+	data := slices.Clone(args)
+	data = append(data, scopePairs()...)
+	msg, data = expandMessages(msg, data)
+	area, data := splitArea(data)
+	severity, data := splitSeverity(data)
+	id, data := splitID(data)
+	data = withAreaMeta(area, data)
+	r := &report{msg: msg, area: area, severity: severity, id: id, time: now(), args: data}
+	r.simSeed, r.simulated = simSeed()
+	return r
+}
+
+// collect captures the assert data and, if enabled, the current stack.
+func (r *report) collect(c *config) {
+	if c.labels {
+		r.labels = goroutineLabels()
+	}
+	dataMu.RLock()
+	defer dataMu.RUnlock()
+	for k, v := range assertData {
+		r.dumps = append(r.dumps, k, v.Dump())
+	}
+	r.changes = snapshotPairs(r.dumps)
+	r.crumbs = recentBreadcrumbs(maxReportedCrumbs)
+	r.logs = recentLogs()
+	r.system = systemPairs(c)
+	if c.stack && r.stack == nil {
+		r.stack = debug.Stack()
+	}
+}
+
+// pairs returns every key/value pair of the report in output order.
+func (r *report) pairs() []any {
+	pairs := []any{
+		"msg", r.msg,
+		"area", r.area,
+		"severity", r.severity,
+	}
+	if r.id != "" {
+		pairs = append(pairs, "id", r.id)
+	}
+	if r.expr != "" {
+		pairs = append(pairs, "expression", r.expr)
+	}
+	if r.dropped > 0 {
+		pairs = append(pairs, "suppressed", fmt.Sprintf("%d similar failures", r.dropped))
+	}
+	if r.fingerprint != "" {
+		pairs = append(pairs, "fingerprint", r.fingerprint)
+	}
+	if r.simulated {
+		pairs = append(pairs, "sim.seed", r.simSeed)
+	}
+	if r.siteFailures > 0 {
+		pairs = append(pairs, "site", fmt.Sprintf("%d failures in %d evaluations", r.siteFailures, r.siteEvals))
+	}
+	pairs = append(pairs, r.args...)
+	pairs = append(pairs, r.labels...)
+	pairs = append(pairs, r.dumps...)
+	pairs = append(pairs, r.changes...)
+	pairs = append(pairs, r.system...)
+	for _, b := range r.crumbs {
+		pairs = append(pairs, "breadcrumb", b)
+	}
+	for _, l := range r.logs {
+		pairs = append(pairs, "log", l)
+	}
+	return pairs
+}
+
+// bufPool holds the buffers reports are rendered into. Rendering the whole
+// report before writing it keeps reports from concurrent failures from
+// interleaving, and lets each sink receive it in a single Write.
+var bufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// maxPooledBuf bounds the buffers returned to bufPool, so one huge report
+// does not pin its memory for the life of the process.
+const maxPooledBuf = 64 << 10
+
+func getBuf() *bytes.Buffer {
+	return bufPool.Get().(*bytes.Buffer)
+}
+
+func putBuf(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuf {
+		return
+	}
+	b.Reset()
+	bufPool.Put(b)
+}
+
+func (r *report) writeTo(w io.Writer, f Format) {
+	b := getBuf()
+	defer putBuf(b)
+
+	r.renderFormat(b, f)
+	w.Write(b.Bytes())
+}
+
+func (r *report) render(b *bytes.Buffer) {
+	b.WriteString("ASSERT\n")
+	pairs := r.pairs()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		fmt.Fprintf(b, "   %s=%v\n", pairs[i], pairs[i+1])
+	}
+	if r.stack != nil {
+		b.Write(r.stack)
+		b.WriteByte('\n')
+	}
+}
+
+// summary renders the message and caller data on one line.
+func (r *report) summary() string {
+	var b strings.Builder
+	b.WriteString(r.msg)
+	for i := 0; i+1 < len(r.args); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", r.args[i], r.args[i+1])
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+//go:build !tinygo
+
+package assert
+
+// IncludeResources controls whether reports include the process's open file
+// descriptors, how many of them are sockets, and its descriptor limit, since
+// invariants often break because descriptors ran out:
+//
+//	fd.open=1019
+//	fd.sockets=1002
+//	fd.limit=1024
+//	fd.limit.hard=524288
+//
+// Descriptors are counted on Unix systems that list them in /proc/self/fd or
+// /dev/fd; elsewhere the section is left out. It is off by default.
+func IncludeResources(on bool) {
+	updateConfig(func(c *config) { c.resources = on })
+}
+
+// resourcePairs returns the descriptor counts and limits of the process.
+func resourcePairs() []any {
+	var pairs []any
+	if open, sockets, ok := descriptorCounts(); ok {
+		pairs = append(pairs, "fd.open", open, "fd.sockets", sockets)
+	}
+	if soft, hard, ok := descriptorLimit(); ok {
+		pairs = append(pairs, "fd.limit", soft, "fd.limit.hard", hard)
+	}
+	return pairs
+}
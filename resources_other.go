@@ -0,0 +1,11 @@
+//go:build !unix && !tinygo
+
+package assert
+
+func descriptorCounts() (open, sockets int, ok bool) {
+	return 0, 0, false
+}
+
+func descriptorLimit() (soft, hard string, ok bool) {
+	return "", "", false
+}
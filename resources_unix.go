@@ -0,0 +1,53 @@
+//go:build unix && !tinygo
+
+package assert
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// descriptorCounts returns the number of open file descriptors of the
+// process and how many of them are sockets.
+func descriptorCounts() (open, sockets int, ok bool) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		if entries, err = os.ReadDir("/dev/fd"); err != nil {
+			return 0, 0, false
+		}
+	}
+	for _, e := range entries {
+		fd, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		var st syscall.Stat_t
+		if syscall.Fstat(fd, &st) != nil {
+			continue // the descriptor of the listing itself, closed by now
+		}
+		open++
+		if st.Mode&syscall.S_IFMT == syscall.S_IFSOCK {
+			sockets++
+		}
+	}
+	return open, sockets, true
+}
+
+// descriptorLimit returns the soft and hard RLIMIT_NOFILE of the process,
+// formatted as numbers or "unlimited".
+func descriptorLimit() (soft, hard string, ok bool) {
+	var lim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &lim); err != nil {
+		return "", "", false
+	}
+	return rlimitString(uint64(lim.Cur)), rlimitString(uint64(lim.Max)), true
+}
+
+func rlimitString(v uint64) string {
+	// RLIM_INFINITY is declared as -1 on some systems.
+	if int64(v) == syscall.RLIM_INFINITY {
+		return "unlimited"
+	}
+	return strconv.FormatUint(v, 10)
+}
@@ -0,0 +1,95 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"io"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+)
+
+// routeFiles holds the files opened by RouteFile, by area, so that they are
+// closed when their route is replaced or removed.
+var routeFiles struct {
+	sync.Mutex
+	m map[string]*os.File
+}
+
+// Route sends reports of failures in area and its sub-areas to w instead of
+// the output set with ToWriter, so each team sharing a binary gets its own
+// sink. The most specific route wins. A nil w removes the route.
+//
+//	assert.Route("payments", asserthttp.Webhook(paymentsHook))
+//	assert.RouteFile("cache", "/var/log/cache-asserts.log")
+//
+// Routes do not apply while ToJournal is in effect.
+func Route(area string, w io.Writer) {
+	routeFiles.Lock()
+	defer routeFiles.Unlock()
+	setRoute(area, w)
+	if f := routeFiles.m[area]; f != nil {
+		delete(routeFiles.m, area)
+		f.Close()
+	}
+}
+
+// setRoute routes area to w. routeFiles must be locked.
+func setRoute(area string, w io.Writer) {
+	updateConfig(func(c *config) {
+		next := maps.Clone(c.routes)
+		if next == nil {
+			next = map[string]io.Writer{}
+		}
+		if w == nil {
+			delete(next, area)
+		} else {
+			next[area] = w
+		}
+		c.routes = next
+	})
+}
+
+// RouteFile routes failures in area to the file at path, which is created if
+// needed and appended to. The file is closed when the route is replaced or
+// removed.
+func RouteFile(area, path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	routeFiles.Lock()
+	defer routeFiles.Unlock()
+	setRoute(area, f)
+	if old := routeFiles.m[area]; old != nil {
+		old.Close()
+	}
+	if routeFiles.m == nil {
+		routeFiles.m = map[string]*os.File{}
+	}
+	routeFiles.m[area] = f
+	return nil
+}
+
+// Routes returns the routes set with Route, mapping each area to its writer.
+func Routes() map[string]io.Writer {
+	return maps.Clone(loadConfig().routes)
+}
+
+// outputFor returns the writer for reports of failures in area.
+func (c *config) outputFor(area string) io.Writer {
+	if len(c.routes) > 0 {
+		for a := area; ; {
+			if w, ok := c.routes[a]; ok {
+				return w
+			}
+			i := strings.LastIndexByte(a, '.')
+			if i < 0 {
+				break
+			}
+			a = a[:i]
+		}
+	}
+	return c.output()
+}
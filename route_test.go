@@ -0,0 +1,30 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRouteFileReplaced(t *testing.T) {
+	defer currentConfig.Store(loadConfig())
+	dir := t.TempDir()
+	if err := RouteFile("cache", filepath.Join(dir, "a.log")); err != nil {
+		t.Fatal(err)
+	}
+	first := Routes()["cache"].(*os.File)
+	if err := RouteFile("cache", filepath.Join(dir, "b.log")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("write to the replaced route's file: %v, want it closed", err)
+	}
+	second := Routes()["cache"].(*os.File)
+	Route("cache", nil)
+	if _, err := second.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("write to the removed route's file: %v, want it closed", err)
+	}
+}
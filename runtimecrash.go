@@ -0,0 +1,95 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var runtimeCrashMu sync.Mutex
+var runtimeCrashFile string // file the runtime writes crashes to, if any
+
+// CaptureRuntimeCrashes makes the runtime write the report of any crash that
+// ends the process outside this package — an unrecovered panic, a fatal
+// error such as a concurrent map write, or a deadlock — to a file in dir,
+// and makes dir the crash directory with SetCrashDir, so runtime crashes and
+// assertion failures land in one place:
+//
+//	if err := assert.CaptureRuntimeCrashes("/var/crash/payments"); err != nil {
+//		log.Printf("runtime crashes go to stderr only: %v", err)
+//	}
+//
+// The file is named after the time CaptureRuntimeCrashes was called and the
+// process ID, e.g. "runtime-20240102T150405.000-4242.txt", and created up
+// front, since the runtime cannot create files while crashing; it stays empty
+// unless the process crashes. Empty files of processes that are no longer
+// running are removed by the next call for the same directory on Unix. The
+// runtime writes the file itself, so it is neither compressed nor encrypted,
+// and the crash is still printed to standard error.
+//
+// An empty dir stops capturing runtime crashes and leaves the crash
+// directory as it is.
+func CaptureRuntimeCrashes(dir string) error {
+	runtimeCrashMu.Lock()
+	defer runtimeCrashMu.Unlock()
+
+	prev := runtimeCrashFile
+	if dir == "" {
+		if err := debug.SetCrashOutput(nil, debug.CrashOptions{}); err != nil {
+			return err
+		}
+		runtimeCrashFile = ""
+		removeIfEmpty(prev)
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	removeStaleRuntimeCrashFiles(dir)
+	path := filepath.Join(dir, fmt.Sprintf("runtime-%s-%d.txt", now().Format("20060102T150405.000"), os.Getpid()))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	// SetCrashOutput duplicates the descriptor, so f is closed either way.
+	err = debug.SetCrashOutput(f, debug.CrashOptions{})
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	runtimeCrashFile = path
+	removeIfEmpty(prev)
+	SetCrashDir(dir)
+	return nil
+}
+
+// removeStaleRuntimeCrashFiles removes the empty runtime crash files in dir
+// left by processes that exited without crashing.
+func removeStaleRuntimeCrashFiles(dir string) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "runtime-*-*.txt"))
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		pid, err := strconv.Atoi(name[strings.LastIndexByte(name, '-')+1:])
+		if err != nil || pid == os.Getpid() || processRunning(pid) {
+			continue
+		}
+		removeIfEmpty(path)
+	}
+}
+
+func removeIfEmpty(path string) {
+	if path == "" {
+		return
+	}
+	if fi, err := os.Stat(path); err == nil && fi.Size() == 0 {
+		os.Remove(path)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix && !tinygo
+
+package assert
+
+// processRunning reports whether a process with the given ID exists. Without
+// a portable way to tell, every process is assumed to be running, so no
+// runtime crash files are removed.
+func processRunning(pid int) bool {
+	return true
+}
@@ -0,0 +1,14 @@
+//go:build unix && !tinygo
+
+package assert
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processRunning reports whether a process with the given ID exists.
+func processRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,109 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"runtime/debug"
+	rtmetrics "runtime/metrics"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRuntimeMetrics are the runtime/metrics samples suggested for
+// IncludeRuntimeMetrics: the live heap, the GC's goal and cycle count, and
+// GC pauses.
+var DefaultRuntimeMetrics = []string{
+	"/gc/heap/objects:objects",
+	"/gc/heap/live:bytes",
+	"/gc/heap/goal:bytes",
+	"/gc/cycles/total:gc-cycles",
+	"/sched/pauses/total/gc:seconds",
+}
+
+// IncludeRuntimeMetrics makes reports include the named runtime/metrics
+// samples, read when the failure is reported, along with the fraction of CPU
+// time spent in the GC and the last GC pause. Unlike runtime.MemStats,
+// reading them does not stop the world, so the section suits steady-state
+// services:
+//
+//	assert.IncludeRuntimeMetrics(assert.DefaultRuntimeMetrics...)
+//
+//	gc.cpu_fraction=0.031
+//	gc.last_pause=182µs
+//	gc.since_last=1.52s
+//	metric./gc/heap/objects:objects=1843211
+//	metric./gc/heap/live:bytes=201326592
+//	metric./sched/pauses/total/gc:seconds=p50=64µs p99=524µs max=1.04ms
+//
+// Histograms are summarised by percentiles since the process started.
+// Metrics this Go version does not support are reported as "unsupported".
+// Calling IncludeRuntimeMetrics without names removes the section.
+func IncludeRuntimeMetrics(names ...string) {
+	names = slices.Clone(names)
+	updateConfig(func(c *config) { c.runtimeMetrics = names })
+}
+
+var gcCPUSamples = [...]string{
+	"/cpu/classes/gc/total:cpu-seconds",
+	"/cpu/classes/total:cpu-seconds",
+}
+
+// runtimeMetricPairs returns the GC summary and the named samples.
+func runtimeMetricPairs(names []string) []any {
+	s := make([]rtmetrics.Sample, len(gcCPUSamples)+len(names))
+	for i, name := range gcCPUSamples {
+		s[i].Name = name
+	}
+	for i, name := range names {
+		s[len(gcCPUSamples)+i].Name = name
+	}
+	rtmetrics.Read(s)
+
+	var pairs []any
+	if gc, total := s[0].Value, s[1].Value; gc.Kind() == rtmetrics.KindFloat64 && total.Kind() == rtmetrics.KindFloat64 && total.Float64() > 0 {
+		pairs = append(pairs, "gc.cpu_fraction", gc.Float64()/total.Float64())
+	}
+	var stats debug.GCStats
+	stats.Pause = make([]time.Duration, 0, 1)
+	debug.ReadGCStats(&stats)
+	if stats.NumGC > 0 {
+		pairs = append(pairs, "gc.last_pause", stats.Pause[0], "gc.since_last", time.Since(stats.LastGC).Round(time.Millisecond))
+	}
+
+	for _, sample := range s[len(gcCPUSamples):] {
+		pairs = append(pairs, "metric."+sample.Name, metricValue(sample))
+	}
+	return pairs
+}
+
+// metricValue returns a sample's value, with histograms summarised as
+// percentiles.
+func metricValue(s rtmetrics.Sample) any {
+	switch s.Value.Kind() {
+	case rtmetrics.KindUint64:
+		return s.Value.Uint64()
+	case rtmetrics.KindFloat64:
+		return s.Value.Float64()
+	case rtmetrics.KindFloat64Histogram:
+		h := s.Value.Float64Histogram()
+		var total uint64
+		for _, n := range h.Counts {
+			total += n
+		}
+		if total == 0 {
+			return "empty"
+		}
+		format := func(v float64) string {
+			if strings.HasSuffix(s.Name, ":seconds") {
+				return seconds(v).String()
+			}
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		return "p50=" + format(histogramQuantile(h.Buckets, h.Counts, total, 0.5)) +
+			" p99=" + format(histogramQuantile(h.Buckets, h.Counts, total, 0.99)) +
+			" max=" + format(histogramQuantile(h.Buckets, h.Counts, total, 1))
+	}
+	return "unsupported"
+}
@@ -0,0 +1,48 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"sync/atomic"
+)
+
+// sampleCounters are deliberately not sharded: SampledEvery promises exactly
+// every nth call, which needs a single ordering of calls per site.
+var sampleCounters siteState[uintptr, atomic.Uint64]
+
+// Sampled evaluates cond for roughly rate (0 to 1) of the calls and fails if
+// it returns false. It keeps expensive invariants affordable on hot paths.
+func Sampled(rate float64, cond func() bool, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	if rate <= 0 || rate < 1 && randFloat64() >= rate {
+		return
+	}
+	if !cond() {
+		runAssert(msg, data...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+// SampledEvery evaluates cond on the first and then every nth call from the
+// same call site, giving deterministic coverage where random sampling would
+// make failures hard to reproduce.
+func SampledEvery(n uint64, cond func() bool, msg string, data ...any) {
+	if !Enabled() {
+		return
+	}
+	if n == 0 {
+		return
+	}
+	count := sampleCounters.get(callerPC()).Add(1)
+	if (count-1)%n != 0 {
+		return
+	}
+	if !cond() {
+		runAssert(msg, data...)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
@@ -0,0 +1,101 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"math"
+	"runtime"
+	rtmetrics "runtime/metrics"
+	"slices"
+	"sync"
+	"time"
+)
+
+// IncludeScheduler controls whether reports include the scheduler's view of
+// the process: GOMAXPROCS, the number of CPUs and goroutines, and how long
+// goroutines recently waited to run once runnable, for invariants that only
+// break under heavy contention:
+//
+//	sched.gomaxprocs=8
+//	sched.cpus=32
+//	sched.goroutines=18211
+//	sched.latency.p50=12µs
+//	sched.latency.p99=4.2ms
+//	sched.latency.max=31ms
+//	sched.latency.window=2m0.5s
+//
+// Latencies are read from runtime/metrics and cover the window since the
+// previous report that included them, or since the process started. It is
+// off by default.
+func IncludeScheduler(on bool) {
+	updateConfig(func(c *config) { c.sched = on })
+}
+
+var schedMu sync.Mutex
+var schedCounts []uint64    // scheduling latency counts at the last report
+var schedSince = time.Now() // time of the last report, or of startup
+
+// schedulerPairs returns the scheduler section of a report.
+func schedulerPairs() []any {
+	pairs := []any{
+		"sched.gomaxprocs", runtime.GOMAXPROCS(0),
+		"sched.cpus", runtime.NumCPU(),
+		"sched.goroutines", runtime.NumGoroutine(),
+	}
+	s := []rtmetrics.Sample{{Name: "/sched/latencies:seconds"}}
+	rtmetrics.Read(s)
+	if s[0].Value.Kind() != rtmetrics.KindFloat64Histogram {
+		return pairs
+	}
+	h := s[0].Value.Float64Histogram()
+
+	schedMu.Lock()
+	prev, since := schedCounts, schedSince
+	schedCounts, schedSince = slices.Clone(h.Counts), time.Now()
+	schedMu.Unlock()
+
+	counts := slices.Clone(h.Counts)
+	if len(prev) == len(counts) {
+		for i := range counts {
+			counts[i] -= prev[i]
+		}
+	}
+	var total uint64
+	for _, n := range counts {
+		total += n
+	}
+	if total == 0 {
+		return pairs
+	}
+	return append(pairs,
+		"sched.latency.p50", seconds(histogramQuantile(h.Buckets, counts, total, 0.5)),
+		"sched.latency.p99", seconds(histogramQuantile(h.Buckets, counts, total, 0.99)),
+		"sched.latency.max", seconds(histogramQuantile(h.Buckets, counts, total, 1)),
+		"sched.latency.window", time.Since(since).Round(time.Millisecond),
+	)
+}
+
+// histogramQuantile returns the upper bound of the bucket holding quantile q
+// of a runtime/metrics histogram. buckets holds one more boundary than
+// counts.
+func histogramQuantile(buckets []float64, counts []uint64, total uint64, q float64) float64 {
+	rank := uint64(math.Ceil(q * float64(total)))
+	var seen uint64
+	for i, n := range counts {
+		seen += n
+		if n == 0 || seen < rank {
+			continue
+		}
+		bound := buckets[i+1]
+		if math.IsInf(bound, 1) {
+			bound = buckets[i]
+		}
+		return bound
+	}
+	return 0
+}
+
+// seconds converts a runtime/metrics value in seconds to a duration.
+func seconds(v float64) time.Duration {
+	return time.Duration(v * float64(time.Second))
+}
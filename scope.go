@@ -0,0 +1,82 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type scopeFrame struct {
+	name string
+	data []any
+}
+
+var scopeMu sync.Mutex
+var scopes = map[uint64][]scopeFrame{} // open scopes by goroutine ID
+var scoped atomic.Int64                // goroutines with open scopes
+
+// Scope opens a named scope on the calling goroutine and returns the
+// function that closes it. Failures on the goroutine while the scope is open
+// carry the path of open scopes, e.g. "scope=compaction/merge", and the data
+// of each, so context does not have to be threaded through every call:
+//
+//	defer assert.Scope("compaction", "level", level)()
+//
+// Scope data may set the area or severity of the failures inside it; the
+// assertion's own data takes precedence. Scopes belong to the goroutine that
+// opened them and are not inherited by goroutines it starts. Close scopes in
+// the reverse order they were opened, as defer does.
+func Scope(name string, data ...any) func() {
+	goid := currentGoid()
+	frame := scopeFrame{name: name, data: slices.Clone(data)}
+
+	scopeMu.Lock()
+	depth := len(scopes[goid])
+	if depth == 0 {
+		scoped.Add(1)
+	}
+	scopes[goid] = append(scopes[goid], frame)
+	scopeMu.Unlock()
+
+	return func() {
+		scopeMu.Lock()
+		defer scopeMu.Unlock()
+		frames := scopes[goid]
+		if len(frames) <= depth {
+			return // already closed
+		}
+		if depth == 0 {
+			delete(scopes, goid)
+			scoped.Add(-1)
+			return
+		}
+		scopes[goid] = frames[:depth]
+	}
+}
+
+// scopePairs returns the scope path and data of the calling goroutine's open
+// scopes, innermost data first.
+func scopePairs() []any {
+	if scoped.Load() == 0 {
+		return nil
+	}
+	goid := currentGoid()
+	scopeMu.Lock()
+	frames := slices.Clone(scopes[goid])
+	scopeMu.Unlock()
+	if len(frames) == 0 {
+		return nil
+	}
+	names := make([]string, len(frames))
+	for i, f := range frames {
+		names[i] = f.name
+	}
+	pairs := []any{"scope", strings.Join(names, "/")}
+	for i := len(frames) - 1; i >= 0; i-- {
+		pairs = append(pairs, frames[i].data...)
+	}
+	return pairs
+}
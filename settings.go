@@ -0,0 +1,240 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sync"
+	"time"
+)
+
+// Settings is a set of configuration changes that ApplySettings makes at
+// once. Nil fields are left as they are; an empty, non-nil map or list
+// removes what it configures. Its JSON form is the document read by
+// LoadSettingsFile and served to asserthttp.PollSettings:
+//
+//	{
+//	  "enabled": true,
+//	  "mode": "warn",
+//	  "min_severity": "error",
+//	  "format": "json",
+//	  "stack": true,
+//	  "areas": {"storage": false, "storage.wal": true},
+//	  "output": "/var/log/asserts.log",
+//	  "routes": {"payments": "/var/log/payments-asserts.log"},
+//	  "rate_limit": {"count": 100, "per": "1m"},
+//	  "circuit_breaker": {"per_second": 50, "summary_every": "10s"},
+//	  "policy": ["storage * exit", "ui error sample 0.01"],
+//	  "suppressions": ["wal-order warn"]
+//	}
+//
+// Outputs and routes name "stderr", "stdout" or a file to append to, and are
+// only accepted from local files: asserthttp.PollSettings rejects documents
+// that set them.
+type Settings struct {
+	Enabled        *bool              `json:"enabled,omitempty"`
+	Mode           *Mode              `json:"mode,omitempty"`
+	MinSeverity    *Severity          `json:"min_severity,omitempty"`
+	Format         *Format            `json:"format,omitempty"`
+	Stack          *bool              `json:"stack,omitempty"`
+	Areas          map[string]bool    `json:"areas,omitempty"`  // see EnableArea and DisableArea
+	Output         *string            `json:"output,omitempty"` // see ToWriter
+	Routes         map[string]string  `json:"routes,omitempty"` // see Route
+	RateLimit      *RateLimitSettings `json:"rate_limit,omitempty"`
+	CircuitBreaker *BreakerSettings   `json:"circuit_breaker,omitempty"`
+	Policy         []Rule             `json:"policy,omitempty"`       // sampling rates are ActionSample rules
+	Suppressions   []Suppression      `json:"suppressions,omitempty"` // see SetSuppressions
+}
+
+// RateLimitSettings are the arguments of SetRateLimit.
+type RateLimitSettings struct {
+	Count int
+	Per   time.Duration
+}
+
+// BreakerSettings are the arguments of SetCircuitBreaker.
+type BreakerSettings struct {
+	PerSecond    int
+	SummaryEvery time.Duration
+}
+
+type rateLimitJSON struct {
+	Count int    `json:"count"`
+	Per   string `json:"per"`
+}
+
+// MarshalJSON encodes l with its interval as a duration string.
+func (l RateLimitSettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rateLimitJSON{l.Count, l.Per.String()})
+}
+
+// UnmarshalJSON decodes l with its interval as a duration string.
+func (l *RateLimitSettings) UnmarshalJSON(b []byte) error {
+	var v rateLimitJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	per, err := time.ParseDuration(v.Per)
+	if err != nil {
+		return err
+	}
+	*l = RateLimitSettings{v.Count, per}
+	return nil
+}
+
+type breakerJSON struct {
+	PerSecond    int    `json:"per_second"`
+	SummaryEvery string `json:"summary_every"`
+}
+
+// MarshalJSON encodes b with its summary interval as a duration string.
+func (b BreakerSettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(breakerJSON{b.PerSecond, b.SummaryEvery.String()})
+}
+
+// UnmarshalJSON decodes b with its summary interval as a duration string.
+func (b *BreakerSettings) UnmarshalJSON(data []byte) error {
+	var v breakerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	every, err := time.ParseDuration(v.SummaryEvery)
+	if err != nil {
+		return err
+	}
+	*b = BreakerSettings{v.PerSecond, every}
+	return nil
+}
+
+// ApplySettings applies the changes in s. The settings held in the package
+// configuration change in a single step, so no failure sees some of them
+// without the others; area rules, the rate limit and the circuit breaker
+// follow right after. Outputs are opened first, and if one cannot be,
+// nothing changes.
+func ApplySettings(s Settings) error {
+	var output io.Writer
+	if s.Output != nil {
+		w, err := settingsOutput(*s.Output)
+		if err != nil {
+			return err
+		}
+		output = w
+	}
+	var routes map[string]io.Writer
+	if s.Routes != nil {
+		routes = make(map[string]io.Writer, len(s.Routes))
+		for area, name := range s.Routes {
+			w, err := settingsOutput(name)
+			if err != nil {
+				return err
+			}
+			routes[area] = w
+		}
+	}
+	policy := append([]Rule(nil), s.Policy...)
+	suppressions := append([]Suppression(nil), s.Suppressions...)
+
+	updateConfig(func(c *config) {
+		if s.Enabled != nil {
+			c.disabled = !*s.Enabled
+		}
+		if s.Mode != nil {
+			c.mode = *s.Mode
+		}
+		if s.MinSeverity != nil {
+			c.minSeverity = *s.MinSeverity
+		}
+		if s.Format != nil {
+			c.format = *s.Format
+		}
+		if s.Stack != nil {
+			c.stack = *s.Stack
+		}
+		if output != nil {
+			c.writer = output
+			c.journal = nil
+		}
+		if routes != nil {
+			c.routes = routes
+		}
+		if s.Policy != nil {
+			c.policy = policy
+		}
+		if s.Suppressions != nil {
+			c.suppressions = suppressions
+		}
+	})
+	if s.Areas != nil {
+		areaMu.Lock()
+		next := maps.Clone(s.Areas)
+		areaRules.Store(&next)
+		areaMu.Unlock()
+	}
+	if s.RateLimit != nil {
+		SetRateLimit(s.RateLimit.Count, s.RateLimit.Per)
+	}
+	if s.CircuitBreaker != nil {
+		SetCircuitBreaker(s.CircuitBreaker.PerSecond, s.CircuitBreaker.SummaryEvery)
+	}
+	return nil
+}
+
+var settingsFilesMu sync.Mutex
+var settingsFiles = map[string]*os.File{}
+
+// settingsOutput returns the writer an output setting names. Files stay open
+// for the life of the process, so applying the same settings again reuses
+// them.
+func settingsOutput(name string) (io.Writer, error) {
+	switch name {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	}
+	settingsFilesMu.Lock()
+	defer settingsFilesMu.Unlock()
+	if f, ok := settingsFiles[name]; ok {
+		return f, nil
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	settingsFiles[name] = f
+	return f, nil
+}
+
+// CurrentSettings returns the settings in effect. Outputs, which are
+// writers rather than names, are left out.
+func CurrentSettings() Settings {
+	c := loadConfig()
+	enabled, mode, minSeverity, format, stack := !c.disabled, c.mode, c.minSeverity, c.format, c.stack
+	return Settings{
+		Enabled:      &enabled,
+		Mode:         &mode,
+		MinSeverity:  &minSeverity,
+		Format:       &format,
+		Stack:        &stack,
+		Areas:        AreaRules(),
+		Policy:       append([]Rule{}, c.policy...),
+		Suppressions: append([]Suppression{}, c.suppressions...),
+	}
+}
+
+// ParseSettings decodes settings from their JSON form. Unknown fields are
+// errors, so that a misspelt setting is not silently ignored.
+func ParseSettings(b []byte) (Settings, error) {
+	var s Settings
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return Settings{}, fmt.Errorf("assert: parsing settings: %w", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,85 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// LoadSettingsFile reads a JSON settings file, in the form described by
+// Settings, and applies it with ApplySettings.
+func LoadSettingsFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	s, err := ParseSettings(b)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := ApplySettings(s); err != nil {
+		return fmt.Errorf("assert: applying settings %s: %w", path, err)
+	}
+	return nil
+}
+
+// WatchSettingsFile loads the settings file at path and reloads it whenever
+// it changes, checking every interval, and on Unix systems also when the
+// process receives SIGHUP. Ops can then adjust a single host during an
+// incident by editing the file. Reload errors are reported on the
+// configured output and leave the settings in effect unchanged. The error of
+// the first load is returned; watching continues either way until the
+// returned function is called. A non-positive interval is an error, and
+// nothing is loaded or watched.
+//
+//	stop, err := assert.WatchSettingsFile("/etc/myservice/assert.json", 5*time.Second)
+func WatchSettingsFile(path string, interval time.Duration) (stop func(), err error) {
+	if interval <= 0 {
+		return func() {}, fmt.Errorf("assert: non-positive settings watch interval %v", interval)
+	}
+	err = LoadSettingsFile(path)
+	last, _ := fileVersion(path)
+
+	done := make(chan struct{})
+	hup := make(chan os.Signal, 1)
+	notifyHangup(hup)
+	go func() {
+		defer signal.Stop(hup)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			force := false
+			select {
+			case <-done:
+				return
+			case <-t.C:
+			case <-hup:
+				force = true
+			}
+			v, err := fileVersion(path)
+			if err != nil || !force && v == last {
+				continue
+			}
+			last = v
+			if err := LoadSettingsFile(path); err != nil {
+				fmt.Fprintf(loadConfig().output(), "assert: reloading settings: %v\n", err)
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, err
+}
+
+// fileVersion identifies the contents of a file by its modification time
+// and size.
+func fileVersion(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%d", fi.ModTime().UnixNano(), fi.Size()), nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !tinygo
+
+package assert
+
+import "os"
+
+// notifyHangup does nothing: there is no SIGHUP outside Unix systems.
+func notifyHangup(ch chan os.Signal) {}
@@ -0,0 +1,14 @@
+//go:build unix && !tinygo
+
+package assert
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyHangup relays SIGHUP to ch.
+func notifyHangup(ch chan os.Signal) {
+	signal.Notify(ch, syscall.SIGHUP)
+}
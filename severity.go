@@ -0,0 +1,96 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks how serious a failed assertion is.
+type Severity int32
+
+const (
+	SeverityDebug Severity = iota
+	SeverityWarn
+	SeverityError
+	SeverityFatal
+)
+
+// SeverityKey is the data key used to set an assertion's severity.
+// Assertions without one are SeverityFatal.
+//
+//	assert.Assert(ok, "cache entry is stale", assert.SeverityKey, assert.SeverityWarn)
+//
+// It is namespaced so that an assertion's own "severity" data is reported
+// rather than taken as its severity.
+const SeverityKey = "assert.severity"
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityWarn:
+		return "warn"
+	case SeverityError:
+		return "error"
+	case SeverityFatal:
+		return "fatal"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText encodes s as its name, so that it reads well in JSON.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a name as returned by Severity.String.
+func (s *Severity) UnmarshalText(b []byte) error {
+	v, err := ParseSeverity(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// ParseSeverity parses the name of a severity as returned by
+// Severity.String.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return SeverityDebug, nil
+	case "warn", "warning":
+		return SeverityWarn, nil
+	case "error":
+		return SeverityError, nil
+	case "fatal":
+		return SeverityFatal, nil
+	}
+	return SeverityFatal, fmt.Errorf("assert: unknown severity %q", s)
+}
+
+// SetMinSeverity sets the least severe failure that is enforced according
+// to the current mode. Less severe failures are only reported, as in
+// ModeWarn. The default is SeverityError, so raising it to SeverityFatal in
+// production turns error-level invariants into log-only checks.
+func SetMinSeverity(s Severity) {
+	updateConfig(func(c *config) { c.minSeverity = s })
+}
+
+// MinSeverity returns the threshold set by SetMinSeverity.
+func MinSeverity() Severity {
+	return loadConfig().minSeverity
+}
+
+// splitSeverity removes the severity pair from data, returning the severity
+// and the remaining data.
+func splitSeverity(data []any) (Severity, []any) {
+	if v, rest, ok := takeKey(data, SeverityKey); ok {
+		if s, ok := v.(Severity); ok {
+			return s, rest
+		}
+	}
+	return SeverityFatal, data
+}
@@ -0,0 +1,61 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+// State updated from many goroutines at once is split into shards, each on
+// its own cache line, so concurrent assertions do not serialize on a single
+// mutex or contend for one atomic. Writers pick a shard at random; readers
+// combine all shards.
+
+// shardCount is a power of two no smaller than GOMAXPROCS at startup.
+var shardCount = 1 << bits.Len(uint(runtime.GOMAXPROCS(0)-1))
+
+// cacheLine is the padding unit that keeps shards from false sharing.
+const cacheLine = 64
+
+func shardIndex() int {
+	return int(rand.Uint32() & uint32(shardCount-1))
+}
+
+type paddedUint64 struct {
+	atomic.Uint64
+	_ [cacheLine - 8]byte
+}
+
+// shardedCounter is a counter for hot paths. Its zero value is ready to use;
+// shards are allocated on the first Add.
+type shardedCounter struct {
+	shards atomic.Pointer[[]paddedUint64]
+}
+
+func (c *shardedCounter) Add(n uint64) {
+	shards := c.shards.Load()
+	if shards == nil {
+		s := make([]paddedUint64, shardCount)
+		if !c.shards.CompareAndSwap(nil, &s) {
+			shards = c.shards.Load()
+		} else {
+			shards = &s
+		}
+	}
+	(*shards)[shardIndex()].Add(n)
+}
+
+func (c *shardedCounter) Load() uint64 {
+	shards := c.shards.Load()
+	if shards == nil {
+		return 0
+	}
+	var sum uint64
+	for i := range *shards {
+		sum += (*shards)[i].Load()
+	}
+	return sum
+}
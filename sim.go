@@ -0,0 +1,97 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of time used for report and breadcrumb timestamps,
+// rate limiting and Watch intervals. Simulations replace it to make time
+// deterministic.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Simulation configures the package for deterministic simulation testing.
+type Simulation struct {
+	// Seed identifies the run. It is included in every failure report as
+	// "sim.seed" so a failing run can be reproduced, and seeds Rand if that
+	// is nil.
+	Seed uint64
+	// Clock replaces the real clock. Nil keeps the real clock.
+	Clock Clock
+	// Rand replaces the random source of sampling. Nil uses a generator
+	// seeded with Seed.
+	Rand *rand.Rand
+}
+
+type simulation struct {
+	seed  uint64
+	clock Clock
+	mu    sync.Mutex
+	rng   *rand.Rand
+}
+
+var sim atomic.Pointer[simulation]
+
+// Simulate makes the package use the clock and random source of s until
+// EndSimulation is called.
+func Simulate(s Simulation) {
+	st := &simulation{seed: s.Seed, clock: s.Clock, rng: s.Rand}
+	if st.clock == nil {
+		st.clock = realClock{}
+	}
+	if st.rng == nil {
+		st.rng = rand.New(rand.NewPCG(s.Seed, s.Seed))
+	}
+	sim.Store(st)
+}
+
+// EndSimulation restores the real clock and random source.
+func EndSimulation() {
+	sim.Store(nil)
+}
+
+// clock returns the clock in use.
+func clock() Clock {
+	if s := sim.Load(); s != nil {
+		return s.clock
+	}
+	return realClock{}
+}
+
+// now returns the current time of the clock in use.
+func now() time.Time {
+	if s := sim.Load(); s != nil {
+		return s.clock.Now()
+	}
+	return time.Now()
+}
+
+// randFloat64 returns a number in [0, 1) from the random source in use.
+func randFloat64() float64 {
+	if s := sim.Load(); s != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.rng.Float64()
+	}
+	return rand.Float64()
+}
+
+// simSeed returns the seed of the running simulation, if any.
+func simSeed() (uint64, bool) {
+	if s := sim.Load(); s != nil {
+		return s.seed, true
+	}
+	return 0, false
+}
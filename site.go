@@ -0,0 +1,88 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// pkgPath is the import path of this package. Frames from it and its
+// sub-packages are skipped when locating the caller of an assertion.
+const pkgPath = "github.com/bhuvneshuchiha/assert"
+
+// callerPC returns the program counter of the call to the exported assertion
+// that called callerPC. It must be called directly from that function.
+func callerPC() uintptr {
+	var pcs [1]uintptr
+	// Skip runtime.Callers, callerPC and the assertion itself.
+	if runtime.Callers(3, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// siteKey identifies an assertion call site by source position.
+type siteKey struct {
+	file string
+	line int
+}
+
+func (k siteKey) String() string {
+	return fmt.Sprintf("%s:%d", k.file, k.line)
+}
+
+// failureFrame returns the first frame outside this module, which is the
+// user's call to the failing assertion.
+func failureFrame() runtime.Frame {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !more || !internalFunc(f.Function) {
+			return f
+		}
+	}
+}
+
+// formatStack formats pcs, as returned by runtime.Callers, like a goroutine
+// stack trace without arguments and offsets.
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d", f.Function, f.File, f.Line)
+		if !more {
+			return b.String()
+		}
+		b.WriteByte('\n')
+	}
+}
+
+func internalFunc(name string) bool {
+	return strings.HasPrefix(name, pkgPath+".") || strings.HasPrefix(name, pkgPath+"/")
+}
+
+// siteState holds per call site state of type T, created on first use.
+type siteState[K comparable, T any] struct {
+	m sync.Map // K -> *T
+}
+
+func (s *siteState[K, T]) get(key K) *T {
+	if v, ok := s.m.Load(key); ok {
+		return v.(*T)
+	}
+	v, _ := s.m.LoadOrStore(key, new(T))
+	return v.(*T)
+}
+
+func (s *siteState[K, T]) each(fn func(K, *T)) {
+	s.m.Range(func(k, v any) bool {
+		fn(k.(K), v.(*T))
+		return true
+	})
+}
@@ -0,0 +1,160 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// tracking is set while passing assertions must be observed, for TrackSites
+// or SetMetrics. It is checked on the passing path of every assertion, so it
+// is a bare atomic rather than part of config.
+var tracking atomic.Bool
+
+// TrackSites turns the site registry on or off. While on, every assertion
+// call site is recorded the first time it executes, passing or failing, and
+// its evaluations and failures are counted, so Sites can show which
+// invariants a deployed binary actually checks and which never run.
+//
+// Tracking is opt-in and costly. The counters are atomic, but finding the
+// call site they belong to takes a stack walk on every evaluation: a passing
+// assertion costs about a microsecond instead of a nanosecond. Turn it on
+// for audits and in staging, not in hot production paths.
+func TrackSites(on bool) {
+	sitesOn.Store(on)
+	updateTracking()
+}
+
+// Site describes an assertion call site recorded by TrackSites.
+type Site struct {
+	File     string    `json:"file"`
+	Line     int       `json:"line"`
+	Function string    `json:"function"`
+	Message  string    `json:"message"`
+	Area     string    `json:"area"`
+	First    time.Time `json:"first"` // when the site first executed
+
+	Evaluations uint64 `json:"evaluations"` // evaluations while tracking, including failures
+	Failures    uint64 `json:"failures"`    // failures while tracking
+}
+
+type siteRecord struct {
+	site  atomic.Pointer[Site]
+	evals shardedCounter
+	fails atomic.Uint64
+}
+
+var sites siteState[uintptr, siteRecord]
+
+// pcInternal caches whether all the functions at a program counter belong
+// to this module.
+var pcInternal sync.Map // uintptr -> bool
+
+// Sites returns the recorded call sites ordered by file and line.
+func Sites() []Site {
+	var list []Site
+	sites.each(func(_ uintptr, rec *siteRecord) {
+		if s := rec.site.Load(); s != nil {
+			site := *s
+			site.Evaluations = rec.evals.Load()
+			site.Failures = rec.fails.Load()
+			list = append(list, site)
+		}
+	})
+	slices.SortFunc(list, func(a, b Site) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+	return list
+}
+
+// WriteSites writes the recorded call sites to w as a table. DumpState
+// includes it while TrackSites is on.
+func WriteSites(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SITE\tAREA\tEVALUATIONS\tFAILURES\tMESSAGE")
+	for _, s := range Sites() {
+		fmt.Fprintf(tw, "%s:%d\t%s\t%d\t%d\t%s\n", s.File, s.Line, s.Area, s.Evaluations, s.Failures, s.Message)
+	}
+	tw.Flush()
+}
+
+// trackSite records the call site of the assertion being evaluated and
+// returns its record for counting.
+func trackSite(msg, area string) *siteRecord {
+	pc := assertionPC()
+	rec := sites.get(pc)
+	if rec.site.Load() == nil {
+		rec.site.CompareAndSwap(nil, newSite(pc, msg, area))
+	}
+	return rec
+}
+
+// assertionPC returns the program counter of the user's call to the
+// assertion being evaluated: the first one on the stack that is not
+// entirely inside this module.
+func assertionPC() uintptr {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	for _, pc := range pcs[:n] {
+		if !isInternalPC(pc) {
+			return pc
+		}
+	}
+	return 0
+}
+
+func isInternalPC(pc uintptr) bool {
+	if v, ok := pcInternal.Load(pc); ok {
+		return v.(bool)
+	}
+	internal := true
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		f, more := frames.Next()
+		if !internalFunc(f.Function) {
+			internal = false
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	pcInternal.Store(pc, internal)
+	return internal
+}
+
+func newSite(pc uintptr, msg, area string) *Site {
+	s := &Site{Message: msg, Area: area, First: now()}
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		f, more := frames.Next()
+		if !internalFunc(f.Function) {
+			s.File, s.Line, s.Function = f.File, f.Line, f.Function
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	return s
+}
+
+// areaOf returns the area data tags an assertion with.
+func areaOf(data []any) string {
+	for i := 0; i+1 < len(data); i += 2 {
+		if data[i] == AreaKey {
+			if a, ok := data[i+1].(string); ok {
+				return a
+			}
+		}
+	}
+	return defaultArea
+}
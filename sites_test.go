@@ -0,0 +1,27 @@
+//go:build !tinygo
+
+package assert
+
+import "testing"
+
+func TestTrackSites(t *testing.T) {
+	TrackSites(true)
+	defer TrackSites(false)
+	defer EnableArea("sites.off")
+	DisableArea("sites.off")
+
+	for i := range 3 {
+		fails(t, func() { Assert(i != 1, "tracked site") })
+		fails(t, func() { Assert(i != 1, "disabled site", AreaKey, "sites.off") })
+	}
+	counts := map[string][2]uint64{}
+	for _, s := range Sites() {
+		counts[s.Message] = [2]uint64{s.Evaluations, s.Failures}
+	}
+	if got := counts["tracked site"]; got != [2]uint64{3, 1} {
+		t.Errorf("tracked site: evaluations, failures = %v, want [3 1]", got)
+	}
+	if got, ok := counts["disabled site"]; ok {
+		t.Errorf("site in a disabled area recorded: %v", got)
+	}
+}
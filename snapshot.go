@@ -0,0 +1,134 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DataSnapshot is the registered assert data as it was dumped at one point,
+// taken with Snapshot.
+type DataSnapshot struct {
+	Time   time.Time
+	Values map[string]string // Dump of each registered AssertData by key
+}
+
+// ChangeKind says how an assert data value changed between snapshots.
+type ChangeKind int
+
+const (
+	ChangeModified ChangeKind = iota // the key's dump differs
+	ChangeAdded                      // the key was registered after the snapshot
+	ChangeRemoved                    // the key was removed after the snapshot
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeModified:
+		return "changed"
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// DataChange is a difference between two snapshots of the assert data.
+// Before is empty for ChangeAdded keys and After for ChangeRemoved ones.
+type DataChange struct {
+	Key    string
+	Kind   ChangeKind
+	Before string
+	After  string
+}
+
+var lastSnapshot atomic.Pointer[DataSnapshot]
+
+// Snapshot dumps every registered AssertData and returns the result. Take
+// one at checkpoints, such as the end of a transaction or a compaction; until
+// the next one, failure reports list the assert data that changed since it,
+// since drift between checkpoints is often what broke the invariant:
+//
+//	s := assert.Snapshot()
+//	applyBatch(b)
+//	for _, c := range s.Diff() {
+//		log.Printf("%s %s: %q -> %q", c.Kind, c.Key, c.Before, c.After)
+//	}
+func Snapshot() *DataSnapshot {
+	s := takeSnapshot()
+	lastSnapshot.Store(s)
+	return s
+}
+
+func takeSnapshot() *DataSnapshot {
+	dataMu.RLock()
+	defer dataMu.RUnlock()
+	s := &DataSnapshot{Time: now(), Values: make(map[string]string, len(assertData))}
+	for k, v := range assertData {
+		s.Values[k] = v.Dump()
+	}
+	return s
+}
+
+// Diff returns how the assert data changed since s was taken, sorted by key.
+// It does not replace the snapshot reports compare against.
+func (s *DataSnapshot) Diff() []DataChange {
+	return s.DiffTo(takeSnapshot())
+}
+
+// DiffTo returns how the assert data changed between s and the later
+// snapshot next, sorted by key.
+func (s *DataSnapshot) DiffTo(next *DataSnapshot) []DataChange {
+	var changes []DataChange
+	for k, before := range s.Values {
+		after, ok := next.Values[k]
+		switch {
+		case !ok:
+			changes = append(changes, DataChange{Key: k, Kind: ChangeRemoved, Before: before})
+		case after != before:
+			changes = append(changes, DataChange{Key: k, Kind: ChangeModified, Before: before, After: after})
+		}
+	}
+	for k, after := range next.Values {
+		if _, ok := s.Values[k]; !ok {
+			changes = append(changes, DataChange{Key: k, Kind: ChangeAdded, After: after})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// snapshotPairs returns the report pairs for the changes between the latest
+// snapshot and dumps, the assert data key/value pairs of a report. The
+// current values are already in the report, so only what they were is
+// listed.
+func snapshotPairs(dumps []any) []any {
+	s := lastSnapshot.Load()
+	if s == nil {
+		return nil
+	}
+	cur := &DataSnapshot{Values: make(map[string]string, len(dumps)/2)}
+	for i := 0; i+1 < len(dumps); i += 2 {
+		cur.Values[fmt.Sprint(dumps[i])] = fmt.Sprint(dumps[i+1])
+	}
+	changes := s.DiffTo(cur)
+	if len(changes) == 0 {
+		return nil
+	}
+	pairs := []any{"snapshot.age", now().Sub(s.Time).Round(time.Millisecond)}
+	for _, c := range changes {
+		switch c.Kind {
+		case ChangeModified:
+			pairs = append(pairs, "changed."+c.Key, "was "+c.Before)
+		case ChangeAdded:
+			pairs = append(pairs, "changed."+c.Key, "added since snapshot")
+		case ChangeRemoved:
+			pairs = append(pairs, "changed."+c.Key, "removed since snapshot, was "+c.Before)
+		}
+	}
+	return pairs
+}
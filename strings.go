@@ -0,0 +1,25 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NotBlank asserts that s contains something other than white space, for
+// identifiers and configuration values where "" and "  " are equally
+// wrong. The report includes the quoted value and its length in bytes.
+func NotBlank(s string, msg string, data ...any) {
+	if blank := strings.TrimSpace(s) == ""; blank || tracking.Load() {
+		blankChecked(blank, s, msg, data)
+	}
+}
+
+func blankChecked(failed bool, s string, msg string, data []any) {
+	if !failed {
+		passed(msg, data)
+		return
+	}
+	runAssert(msg, append(data, "value", strconv.Quote(s), "len", len(s))...)
+}
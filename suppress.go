@@ -0,0 +1,174 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// IDKey is the data key used to give an assertion a stable ID, by which
+// suppression lists can address it independently of its message and line:
+//
+//	assert.Assert(sorted, "wal entries out of order", assert.IDKey, "wal-order")
+//
+// It is namespaced so that an assertion's own "id" data is reported rather
+// than taken as its ID.
+const IDKey = "assert.id"
+
+// splitID removes the ID pair from data, returning the ID and the remaining
+// data.
+func splitID(data []any) (string, []any) {
+	if v, rest, ok := takeKey(data, IDKey); ok {
+		if id, ok := v.(string); ok {
+			return id, rest
+		}
+	}
+	return "", data
+}
+
+// Suppression mutes or downgrades the failures of one assertion, so a
+// known-bad invariant found in production can be handled by configuration
+// while the fix rolls out.
+type Suppression struct {
+	// Target is the assertion's ID, set with IDKey, or the fingerprint of
+	// its failures.
+	Target string
+	Action Action
+	Rate   float64 // fraction reported by ActionSample
+}
+
+// String formats s as a line of a suppression list.
+func (s Suppression) String() string {
+	line := s.Target + " " + s.Action.String()
+	if s.Action == ActionSample {
+		line += " " + strconv.FormatFloat(s.Rate, 'g', -1, 64)
+	}
+	return line
+}
+
+// MarshalText encodes s as a line of a suppression list.
+func (s Suppression) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a line of a suppression list.
+func (s *Suppression) UnmarshalText(b []byte) error {
+	v, err := parseSuppression(strings.Fields(string(b)))
+	if err != nil {
+		return fmt.Errorf("assert: suppression %q: %w", b, err)
+	}
+	*s = v
+	return nil
+}
+
+// SetSuppressions replaces the suppression list. A suppression overrides
+// the mode and policy for the failures it targets; ActionWarn downgrades
+// them to warnings and ActionIgnore mutes them. Calling SetSuppressions
+// without arguments removes the list. A sample rate outside [0, 1] is an
+// error, and the list is left as it was.
+func SetSuppressions(s ...Suppression) error {
+	for _, sup := range s {
+		if sup.Action == ActionSample {
+			if err := checkRate(sup.Rate); err != nil {
+				return fmt.Errorf("assert: suppression %q: %w", sup, err)
+			}
+		}
+	}
+	s = append([]Suppression(nil), s...)
+	updateConfig(func(c *config) { c.suppressions = s })
+	return nil
+}
+
+// Suppressions returns the list set with SetSuppressions or
+// LoadSuppressions.
+func Suppressions() []Suppression {
+	return append([]Suppression(nil), loadConfig().suppressions...)
+}
+
+// LoadSuppressions reads a suppression list and installs it with
+// SetSuppressions. Each line holds "target action [rate]", where target is
+// an assertion ID or a fingerprint. Blank lines and lines starting with #
+// are ignored:
+//
+//	# target          action  rate
+//	wal-order         warn
+//	9f86d081884c7d65  ignore
+//	cache-stale       sample  0.01
+func LoadSuppressions(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s, err := ParseSuppressions(f)
+	if err != nil {
+		return fmt.Errorf("assert: %s: %w", path, err)
+	}
+	return SetSuppressions(s...)
+}
+
+// ParseSuppressions parses a list in the format read by LoadSuppressions.
+func ParseSuppressions(r io.Reader) ([]Suppression, error) {
+	var list []Suppression
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		s, err := parseSuppression(strings.Fields(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		list = append(list, s)
+	}
+	return list, sc.Err()
+}
+
+func parseSuppression(fields []string) (Suppression, error) {
+	if len(fields) < 2 || len(fields) > 3 {
+		return Suppression{}, fmt.Errorf("want \"target action [rate]\", got %d fields", len(fields))
+	}
+	s := Suppression{Target: fields[0]}
+	a, err := ParseAction(fields[1])
+	if err != nil {
+		return Suppression{}, err
+	}
+	s.Action = a
+	if len(fields) == 3 {
+		if a != ActionSample {
+			return Suppression{}, fmt.Errorf("rate given for action %s", a)
+		}
+		if s.Rate, err = strconv.ParseFloat(fields[2], 64); err != nil {
+			return Suppression{}, fmt.Errorf("bad rate: %w", err)
+		}
+		if err := checkRate(s.Rate); err != nil {
+			return Suppression{}, err
+		}
+	} else if a == ActionSample {
+		return Suppression{}, fmt.Errorf("action sample needs a rate")
+	}
+	return s, nil
+}
+
+// suppressedMode returns the mode for r after the suppression list, and
+// false if it mutes r. m is the mode decided so far. The last suppression
+// matching r wins.
+func (c *config) suppressedMode(r *report, m Mode) (Mode, bool) {
+	for i := len(c.suppressions) - 1; i >= 0; i-- {
+		s := c.suppressions[i]
+		if s.Target != r.fingerprint && (r.id == "" || s.Target != r.id) {
+			continue
+		}
+		if s.Action == ActionDefault {
+			return m, true
+		}
+		return actionMode(s.Action, s.Rate)
+	}
+	return m, true
+}
@@ -0,0 +1,37 @@
+//go:build !tinygo
+
+package assert
+
+import "testing"
+
+func TestSuppressionPrecedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		list   []Suppression
+		id     string
+		want   Mode
+		report bool
+	}{
+		{"none", nil, "wal-order", ModePanic, true},
+		{"other target", []Suppression{{"cache-stale", ActionIgnore, 0}}, "wal-order", ModePanic, true},
+		{"by id", []Suppression{{"wal-order", ActionWarn, 0}}, "wal-order", ModeWarn, true},
+		{"by fingerprint", []Suppression{{"9f86d081884c7d65", ActionIgnore, 0}}, "", ModeWarn, false},
+		{"empty id does not match", []Suppression{{"", ActionIgnore, 0}}, "", ModePanic, true},
+		{"default keeps mode", []Suppression{{"wal-order", ActionDefault, 0}}, "wal-order", ModePanic, true},
+		{
+			"last wins",
+			[]Suppression{{"wal-order", ActionIgnore, 0}, {"9f86d081884c7d65", ActionExit, 0}},
+			"wal-order", ModeExit, true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &config{suppressions: tt.list}
+			r := &report{id: tt.id, fingerprint: "9f86d081884c7d65"}
+			m, ok := c.suppressedMode(r, ModePanic)
+			if m != tt.want || ok != tt.report {
+				t.Errorf("suppressedMode = %v, %v, want %v, %v", m, ok, tt.want, tt.report)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !tinygo
+
+package assert
+
+// systemPairs returns the process and system context sections enabled in c,
+// as key/value pairs for the report.
+func systemPairs(c *config) []any {
+	var pairs []any
+	if c.resources {
+		pairs = append(pairs, resourcePairs()...)
+	}
+	if c.cmdline {
+		pairs = append(pairs, commandLinePairs(c)...)
+	}
+	if c.cgroup {
+		pairs = append(pairs, cgroupPairs()...)
+	}
+	if c.sched {
+		pairs = append(pairs, schedulerPairs()...)
+	}
+	if len(c.runtimeMetrics) > 0 {
+		pairs = append(pairs, runtimeMetricPairs(c.runtimeMetrics)...)
+	}
+	if len(c.env) > 0 {
+		pairs = append(pairs, envPairs(c.env)...)
+	}
+	return pairs
+}
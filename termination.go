@@ -0,0 +1,39 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultTerminationLog is where Kubernetes reads a container's termination
+// message from unless terminationMessagePath says otherwise.
+const DefaultTerminationLog = "/dev/termination-log"
+
+// maxTerminationLog is the size Kubernetes truncates termination messages to.
+const maxTerminationLog = 4096
+
+// SetTerminationLog makes fatal failures write a one-line summary to path
+// before exiting, so "kubectl describe pod" shows why the container died.
+// Use DefaultTerminationLog unless the pod sets terminationMessagePath. An
+// empty path, the default, disables it.
+func SetTerminationLog(path string) {
+	updateConfig(func(c *config) { c.terminationLog = path })
+}
+
+// writeTerminationLog writes the summary of a fatal failure to the
+// termination log.
+func writeTerminationLog(c *config, r *report) {
+	if c.terminationLog == "" {
+		return
+	}
+	msg := fmt.Sprintf("assertion failed at %s:%d: %s", filepath.Base(r.site.file), r.site.line, r.summary())
+	if len(msg) > maxTerminationLog {
+		msg = msg[:maxTerminationLog]
+	}
+	if err := os.WriteFile(c.terminationLog, []byte(msg), 0o644); err != nil {
+		fmt.Fprintf(c.output(), "assert: writing termination log: %v\n", err)
+	}
+}
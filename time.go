@@ -0,0 +1,54 @@
+//go:build !tinygo
+
+package assert
+
+import "time"
+
+// Before asserts that a is before b, such as a lease acquired before it
+// expires. Like time.Time.Before, it compares monotonic clock readings when
+// both times have them, so wall clock changes do not affect it. The report
+// includes both times and b.Sub(a).
+func Before(a, b time.Time, msg string, data ...any) {
+	if ok := a.Before(b); !ok || tracking.Load() {
+		timeChecked(!ok, "before", a, b, msg, data)
+	}
+}
+
+// After asserts that a is after b.
+func After(a, b time.Time, msg string, data ...any) {
+	if ok := a.After(b); !ok || tracking.Load() {
+		timeChecked(!ok, "after", a, b, msg, data)
+	}
+}
+
+// NotZeroTime asserts that t is set, catching timestamps that were never
+// assigned.
+func NotZeroTime(t time.Time, msg string, data ...any) {
+	if zero := t.IsZero(); zero || tracking.Load() {
+		evaluated(zero, msg, data)
+	}
+}
+
+func timeChecked(failed bool, want string, a, b time.Time, msg string, data []any) {
+	if !failed {
+		passed(msg, data)
+		return
+	}
+	clock := "wall"
+	if monotonic(a) && monotonic(b) {
+		clock = "monotonic"
+	}
+	runAssert(msg, append(data,
+		"time.want", "a "+want+" b",
+		"time.a", a.Format(time.RFC3339Nano),
+		"time.b", b.Format(time.RFC3339Nano),
+		"time.diff", b.Sub(a).String(),
+		"time.clock", clock,
+	)...)
+}
+
+// monotonic reports whether t carries a monotonic clock reading, which
+// Round(0) strips.
+func monotonic(t time.Time) bool {
+	return t != t.Round(0)
+}
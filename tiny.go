@@ -0,0 +1,272 @@
+//go:build tinygo
+
+package assert
+
+// This file is the reduced build used under TinyGo. It keeps the core
+// assertion calls source compatible while avoiding reflect, fmt, slog and
+// os.Exit: a failure is formatted into a fixed-size buffer, written to the
+// configured writer (or printed with the print builtin) and then panics.
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// tinyReportSize bounds a report; longer reports are truncated.
+const tinyReportSize = 256
+
+var disabled atomic.Bool
+
+var tinyMu sync.Mutex
+var tinyWriter io.Writer
+var tinyBuf reportBuf
+
+// Enable turns assertion enforcement on. Assertions are enabled by default.
+func Enable() {
+	disabled.Store(false)
+}
+
+// Disable turns assertion enforcement off; every assertion becomes a no-op
+// until Enable is called.
+func Disable() {
+	disabled.Store(true)
+}
+
+// Enabled reports whether assertions are currently enforced.
+func Enabled() bool {
+	return !disabled.Load()
+}
+
+// ToWriter sends reports to w. A nil w prints them with the print builtin,
+// which is the default.
+func ToWriter(w io.Writer) {
+	tinyMu.Lock()
+	defer tinyMu.Unlock()
+	tinyWriter = w
+}
+
+// AssertionError describes a failed assertion. Failed assertions panic with
+// it, and the Check functions return it.
+type AssertionError struct {
+	msg    string
+	report string
+}
+
+func (e *AssertionError) Error() string {
+	return "assertion failed: " + e.report
+}
+
+// Message returns the assertion's message.
+func (e *AssertionError) Message() string {
+	return e.msg
+}
+
+func Assert(truth bool, msg string, data ...any) {
+	if !truth {
+		runAssert(msg, data)
+	}
+}
+
+func Nil(item any, msg string, data ...any) {
+	if item != nil {
+		runAssert(msg, data)
+	}
+}
+
+// NotNil fails if item is nil. Without reflect it cannot see through an
+// interface holding a nil pointer; use NotNilPtr and friends for that.
+func NotNil(item any, msg string, data ...any) {
+	if item == nil {
+		runAssert(msg, data)
+	}
+}
+
+func NotNilPtr[T any](p *T, msg string, data ...any) {
+	if p == nil {
+		runAssert(msg, data)
+	}
+}
+
+func NotNilMap[K comparable, V any](m map[K]V, msg string, data ...any) {
+	if m == nil {
+		runAssert(msg, data)
+	}
+}
+
+func NotNilSlice[T any](s []T, msg string, data ...any) {
+	if s == nil {
+		runAssert(msg, data)
+	}
+}
+
+func NotNilChan[T any](c <-chan T, msg string, data ...any) {
+	if c == nil {
+		runAssert(msg, data)
+	}
+}
+
+func NotNilSendChan[T any](c chan<- T, msg string, data ...any) {
+	if c == nil {
+		runAssert(msg, data)
+	}
+}
+
+func Never(msg string, data ...any) {
+	runAssert(msg, data)
+}
+
+func NoError(err error, msg string, data ...any) {
+	if err != nil {
+		runAssert(msg, append(data, "error", err))
+	}
+}
+
+func Check(truth bool, msg string, data ...any) error {
+	if truth || !Enabled() {
+		return nil
+	}
+	return checkFailure(msg, data)
+}
+
+func CheckNil(item any, msg string, data ...any) error {
+	if item == nil || !Enabled() {
+		return nil
+	}
+	return checkFailure(msg, data)
+}
+
+func CheckNotNil(item any, msg string, data ...any) error {
+	if item != nil || !Enabled() {
+		return nil
+	}
+	return checkFailure(msg, data)
+}
+
+func CheckNoError(err error, msg string, data ...any) error {
+	if err == nil || !Enabled() {
+		return nil
+	}
+	return checkFailure(msg, append(data, "error", err))
+}
+
+func CheckNever(msg string, data ...any) error {
+	if !Enabled() {
+		return nil
+	}
+	return checkFailure(msg, data)
+}
+
+func runAssert(msg string, data []any) {
+	if !Enabled() {
+		return
+	}
+	tinyMu.Lock()
+	tinyBuf.reset()
+	tinyBuf.writeString("ASSERT\n")
+	tinyBuf.writePairs(msg, data, "   ", "\n")
+	if tinyWriter != nil {
+		tinyWriter.Write(tinyBuf.bytes())
+	} else {
+		print(string(tinyBuf.bytes()))
+	}
+	e := &AssertionError{msg: msg, report: summary(msg, data)}
+	tinyMu.Unlock()
+
+	panic(e)
+}
+
+func checkFailure(msg string, data []any) error {
+	return &AssertionError{msg: msg, report: summary(msg, data)}
+}
+
+// summary formats msg and data on one line.
+func summary(msg string, data []any) string {
+	var b reportBuf
+	b.writeString(msg)
+	b.writePairs("", data, " ", "")
+	return string(b.bytes())
+}
+
+// reportBuf is a fixed-size buffer that silently truncates.
+type reportBuf struct {
+	b [tinyReportSize]byte
+	n int
+}
+
+func (b *reportBuf) reset() {
+	b.n = 0
+}
+
+func (b *reportBuf) bytes() []byte {
+	return b.b[:b.n]
+}
+
+func (b *reportBuf) writeString(s string) {
+	b.n += copy(b.b[b.n:], s)
+}
+
+// writePairs writes "msg=<msg>" when msg is not empty, then every key/value
+// pair of data, each preceded by prefix and followed by suffix.
+func (b *reportBuf) writePairs(msg string, data []any, prefix, suffix string) {
+	if msg != "" {
+		b.writeString(prefix + "msg=")
+		b.writeString(msg)
+		b.writeString(suffix)
+	}
+	for i := 0; i+1 < len(data); i += 2 {
+		b.writeString(prefix)
+		b.writeValue(data[i])
+		b.writeString("=")
+		b.writeValue(data[i+1])
+		b.writeString(suffix)
+	}
+}
+
+type stringer interface {
+	String() string
+}
+
+func (b *reportBuf) writeValue(v any) {
+	switch v := v.(type) {
+	case nil:
+		b.writeString("<nil>")
+	case string:
+		b.writeString(v)
+	case error:
+		b.writeString(v.Error())
+	case stringer:
+		b.writeString(v.String())
+	case bool:
+		b.writeString(strconv.FormatBool(v))
+	case int:
+		b.writeString(strconv.FormatInt(int64(v), 10))
+	case int8:
+		b.writeString(strconv.FormatInt(int64(v), 10))
+	case int16:
+		b.writeString(strconv.FormatInt(int64(v), 10))
+	case int32:
+		b.writeString(strconv.FormatInt(int64(v), 10))
+	case int64:
+		b.writeString(strconv.FormatInt(v, 10))
+	case uint:
+		b.writeString(strconv.FormatUint(uint64(v), 10))
+	case uint8:
+		b.writeString(strconv.FormatUint(uint64(v), 10))
+	case uint16:
+		b.writeString(strconv.FormatUint(uint64(v), 10))
+	case uint32:
+		b.writeString(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		b.writeString(strconv.FormatUint(v, 10))
+	case uintptr:
+		b.writeString(strconv.FormatUint(uint64(v), 10))
+	case float32:
+		b.writeString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		b.writeString(strconv.FormatFloat(v, 'g', -1, 64))
+	default:
+		b.writeString("?")
+	}
+}
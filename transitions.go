@@ -0,0 +1,42 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Transitions declares the allowed transitions of a state machine, mapping
+// each state to the states it may move to. Declare it once next to the state
+// type and assert at every mutation point:
+//
+//	var jobTransitions = assert.Transitions[JobState]{
+//		Queued:  {Running, Canceled},
+//		Running: {Done, Failed, Canceled},
+//	}
+//
+//	jobTransitions.Assert(j.state, next, "illegal job transition", "job", j.ID)
+type Transitions[T comparable] map[T][]T
+
+// Allowed reports whether moving from from to to is declared.
+func (t Transitions[T]) Allowed(from, to T) bool {
+	return slices.Contains(t[from], to)
+}
+
+// Assert fails if moving from from to to is not declared. The report names
+// the offending transition and the transitions allowed from from.
+func (t Transitions[T]) Assert(from, to T, msg string, data ...any) {
+	if !t.Allowed(from, to) {
+		t.failed(from, to, msg, data)
+	} else if tracking.Load() {
+		passed(msg, data)
+	}
+}
+
+func (t Transitions[T]) failed(from, to T, msg string, data []any) {
+	runAssert(msg, append(data,
+		"transition", fmt.Sprintf("%v -> %v", from, to),
+		"allowed", fmt.Sprintf("%v", t[from]),
+	)...)
+}
@@ -0,0 +1,148 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// Aligned asserts that p is a multiple of align bytes, which must be a power
+// of two, as required by atomic operations and by codecs that reinterpret
+// buffers. The report includes the address in hex.
+func Aligned(p unsafe.Pointer, align uintptr, msg string, data ...any) {
+	if off := uintptr(p) & (align - 1); off != 0 || tracking.Load() {
+		alignChecked(off != 0, uintptr(p), align, msg, data)
+	}
+}
+
+func alignChecked(failed bool, addr, align uintptr, msg string, data []any) {
+	if !failed {
+		passed(msg, data)
+		return
+	}
+	runAssert(msg, append(data,
+		"unsafe.addr", hex(addr),
+		"unsafe.align", align,
+		"unsafe.offset", addr&(align-1),
+	)...)
+}
+
+type allocation struct {
+	name string
+	mem  []byte
+}
+
+var allocMu sync.RWMutex
+var allocations []allocation
+
+// TrackAllocation registers mem, such as an mmap'd file or an arena, as
+// memory that UnsafeSlice may build slices in. It keeps mem reachable until
+// UntrackAllocation is called with the same name.
+func TrackAllocation(name string, mem []byte) {
+	allocMu.Lock()
+	defer allocMu.Unlock()
+	for i, a := range allocations {
+		if a.name == name {
+			allocations[i].mem = mem
+			return
+		}
+	}
+	allocations = append(allocations, allocation{name: name, mem: mem})
+}
+
+// UntrackAllocation removes the allocation registered under name, typically
+// just before the memory is unmapped.
+func UntrackAllocation(name string) {
+	allocMu.Lock()
+	defer allocMu.Unlock()
+	for i, a := range allocations {
+		if a.name == name {
+			allocations = append(allocations[:i:i], allocations[i+1:]...)
+			return
+		}
+	}
+}
+
+// UnsafeSlice returns unsafe.Slice(p, n) after asserting that p is aligned
+// for T and that the n elements lie within a single allocation registered
+// with TrackAllocation. The report includes the slice's bounds and those of
+// the nearest allocation in hex.
+//
+//	hdr := assert.UnsafeSlice((*Header)(unsafe.Pointer(&m[off])), 1, "header out of bounds")
+func UnsafeSlice[T any](p *T, n int, msg string, data ...any) []T {
+	if Enabled() {
+		size := unsafe.Sizeof(*p)
+		checkUnsafeSlice(uintptr(unsafe.Pointer(p)), size, unsafe.Alignof(*p), n, msg, data)
+	}
+	return unsafe.Slice(p, n)
+}
+
+func checkUnsafeSlice(start, size, align uintptr, n int, msg string, data []any) {
+	if start&(align-1) != 0 {
+		alignChecked(true, start, align, msg, data)
+		return
+	}
+	if n == 0 {
+		if tracking.Load() {
+			passed(msg, data)
+		}
+		return
+	}
+	end := start + size*uintptr(n)
+	nearest, within := findAllocation(start, end)
+	if within {
+		if tracking.Load() {
+			passed(msg, data)
+		}
+		return
+	}
+
+	data = append(data,
+		"unsafe.slice", fmt.Sprintf("[%s, %s)", hex(start), hex(end)),
+		"unsafe.len", n,
+		"unsafe.elem_size", size,
+	)
+	if nearest.mem != nil {
+		lo := uintptr(unsafe.Pointer(unsafe.SliceData(nearest.mem)))
+		data = append(data, "unsafe.alloc", fmt.Sprintf("%s [%s, %s)", nearest.name, hex(lo), hex(lo+uintptr(len(nearest.mem)))))
+	} else {
+		data = append(data, "unsafe.alloc", "none tracked")
+	}
+	// allocMu is released by now: flushes, hooks and observers run by
+	// runAssert may track or untrack allocations.
+	runAssert(msg, data...)
+}
+
+// findAllocation returns the tracked allocation that holds [start, end), and
+// true, or the allocation nearest to start, which is the zero allocation if
+// none is tracked.
+func findAllocation(start, end uintptr) (nearest allocation, within bool) {
+	allocMu.RLock()
+	defer allocMu.RUnlock()
+
+	var distance uintptr
+	for _, a := range allocations {
+		if len(a.mem) == 0 {
+			continue
+		}
+		lo := uintptr(unsafe.Pointer(unsafe.SliceData(a.mem)))
+		hi := lo + uintptr(len(a.mem))
+		if start >= lo && end <= hi && end > start {
+			return a, true
+		}
+		d := start - lo
+		if start < lo {
+			d = lo - start
+		}
+		if nearest.mem == nil || d < distance {
+			nearest, distance = a, d
+		}
+	}
+	return nearest, false
+}
+
+func hex(addr uintptr) string {
+	return fmt.Sprintf("%#x", addr)
+}
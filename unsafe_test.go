@@ -0,0 +1,50 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestUnsafeSlice(t *testing.T) {
+	mem := make([]uint64, 4)
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(&mem[0])), 32)
+	TrackAllocation("test.mem", buf)
+	defer UntrackAllocation("test.mem")
+
+	other := make([]uint64, 4)
+	tests := []struct {
+		name  string
+		fn    func()
+		fails bool
+	}{
+		{"whole", func() { UnsafeSlice(&mem[0], 4, "") }, false},
+		{"tail", func() { UnsafeSlice(&mem[3], 1, "") }, false},
+		{"empty", func() { UnsafeSlice(&other[0], 0, "") }, false},
+		{"past the end", func() { UnsafeSlice(&mem[2], 3, "") }, true},
+		{"untracked", func() { UnsafeSlice(&other[0], 1, "") }, true},
+		{"misaligned", func() { UnsafeSlice((*uint32)(unsafe.Pointer(&buf[1])), 1, "") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fails(t, tt.fn); got != tt.fails {
+				t.Errorf("failed = %v, want %v", got, tt.fails)
+			}
+		})
+	}
+}
+
+func TestUnsafeSliceObserverTracks(t *testing.T) {
+	// An observer that changes the tracked allocations while a failure is
+	// reported must not deadlock.
+	defer observers.Store(observers.Load())
+	OnFailure(func(Failure) {
+		TrackAllocation("test.observer", nil)
+		UntrackAllocation("test.observer")
+	})
+	x := new(uint64)
+	if !fails(t, func() { UnsafeSlice(x, 1, "untracked") }) {
+		t.Error("UnsafeSlice of untracked memory passed")
+	}
+}
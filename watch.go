@@ -0,0 +1,67 @@
+//go:build !tinygo
+
+package assert
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var watchMu sync.Mutex
+var watches = map[string]chan struct{}{}
+
+// Watch runs check every interval in a background goroutine and reports a
+// failure whenever it returns an error. It is meant for global invariants no
+// single call site owns, such as queue depth bounds or reference count
+// balance. Watching a name again replaces the previous check. In ModePanic
+// the panic happens on the watch goroutine and cannot be recovered; in
+// ModeGoexit the watch goroutine ends and the check stops running. A
+// non-positive interval is an error, and nothing is watched.
+//
+//	assert.Watch("queue-depth", time.Second, func() error {
+//		if n := q.Len(); n > maxDepth {
+//			return fmt.Errorf("depth %d exceeds %d", n, maxDepth)
+//		}
+//		return nil
+//	})
+func Watch(name string, interval time.Duration, check func() error) error {
+	if interval <= 0 {
+		return fmt.Errorf("assert: watch %q: non-positive interval %v", name, interval)
+	}
+	stop := make(chan struct{})
+
+	watchMu.Lock()
+	if old, ok := watches[name]; ok {
+		close(old)
+	}
+	watches[name] = stop
+	watchMu.Unlock()
+
+	go runWatch(name, interval, check, stop)
+	return nil
+}
+
+// Unwatch stops the check registered under name.
+func Unwatch(name string) {
+	watchMu.Lock()
+	defer watchMu.Unlock()
+
+	if stop, ok := watches[name]; ok {
+		close(stop)
+		delete(watches, name)
+	}
+}
+
+func runWatch(name string, interval time.Duration, check func() error, stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-clock().After(interval):
+			if err := check(); err != nil {
+				runAssert("watched invariant failed", "watch", name, "error", err)
+			}
+		}
+	}
+}